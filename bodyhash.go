@@ -0,0 +1,89 @@
+package oauth1
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// BodyHashPolicy selects how a Verifier checks the oauth_body_hash of
+// requests with bodies which are not form-encoded, per the OAuth Request
+// Body Hash extension.
+type BodyHashPolicy int
+
+const (
+	// BodyHashOptional checks the body hash of requests carrying one.
+	BodyHashOptional BodyHashPolicy = iota
+
+	// BodyHashRequired rejects requests without form-encoded body which
+	// lack a body hash, and checks it otherwise.
+	BodyHashRequired
+
+	// BodyHashIgnored never checks body hashes.
+	BodyHashIgnored
+)
+
+// checkBodyHash checks the oauth_body_hash of oauthParams against the body
+// of req according to the BodyHash policy. The body is read and replaced
+// with an equivalent reader. Form-encoded bodies must not be hashed.
+func (v *Verifier) checkBodyHash(req *http.Request, oauthParams url.Values) error {
+	if v.BodyHash == BodyHashIgnored {
+		return nil
+	}
+	bodyHash, ok := oauthParams["oauth_body_hash"]
+	if isFormBody(req) {
+		if ok {
+			return rejectedParameter("oauth_body_hash", nil)
+		}
+		return nil
+	}
+	if !ok {
+		if v.BodyHash == BodyHashRequired {
+			e := verificationError("parameter_absent", nil)
+			e.Problem.ParametersAbsent = []string{"oauth_body_hash"}
+			return e
+		}
+		return nil
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return rejectedParameter("oauth_body_hash", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	h := bodyHashFunc(oauthParams.Get("oauth_signature_method"))()
+	h.Write(body)
+	expected := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	if !constantTimeEqual(expected, bodyHash[0]) {
+		return rejectedParameter("oauth_body_hash", nil)
+	}
+	return nil
+}
+
+// bodyHashFunc returns the hash function of body hashes of requests signed
+// with the named signature method: SHA-256 for the SHA-256 methods, SHA-1
+// otherwise.
+func bodyHashFunc(method string) func() hash.Hash {
+	switch method {
+	case "HMAC-SHA256", "RSA-SHA256":
+		return sha256.New
+	}
+	return sha1.New
+}
+
+// rejectedParameter returns a parameter_rejected VerificationError naming
+// the parameter name.
+func rejectedParameter(name string, err error) *VerificationError {
+	e := verificationError("parameter_rejected", err)
+	e.Problem.ParametersRejected = []string{name}
+	return e
+}
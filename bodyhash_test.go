@@ -0,0 +1,82 @@
+package oauth1
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const outcomeBody = `<?xml version="1.0" encoding="UTF-8"?><imsx_POXEnvelopeRequest/>`
+
+// newOutcomeRequest returns an XML POST with body signed by config with the
+// given oauth_body_hash, if any.
+func newOutcomeRequest(t *testing.T, config *Config, bodyHash, body string) *http.Request {
+	req, err := http.NewRequest("POST", "https://lms.example.com/outcomes", strings.NewReader(body))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/xml")
+	signing := config.Clone()
+	if bodyHash != "" {
+		signing.ExtraParams = url.Values{"oauth_body_hash": {bodyHash}}
+	}
+	assert.Nil(t, signing.SignRequest(req, nil))
+	return req
+}
+
+func TestVerifier_BodyHash(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	v := newTestVerifier()
+	// base64 of the SHA-1 digest of outcomeBody
+	bodyHash := "fqOlD0kItlDAp7trO7Z3psbcpSg="
+
+	req := newOutcomeRequest(t, config, bodyHash, outcomeBody)
+	_, err := v.Verify(req)
+	assert.Nil(t, err)
+	body, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, outcomeBody, string(body))
+
+	tampered := newOutcomeRequest(t, config, bodyHash, strings.Replace(outcomeBody, "UTF-8", "UTF-16", 1))
+	_, err = v.Verify(tampered)
+	verr := assertProblem(t, err, "parameter_rejected")
+	assert.Equal(t, []string{"oauth_body_hash"}, verr.Problem.ParametersRejected)
+
+	v.BodyHash = BodyHashIgnored
+	tampered = newOutcomeRequest(t, config, bodyHash, strings.Replace(outcomeBody, "UTF-8", "UTF-16", 1))
+	_, err = v.Verify(tampered)
+	assert.Nil(t, err)
+
+	v.BodyHash = BodyHashRequired
+	_, err = v.Verify(newOutcomeRequest(t, config, "", outcomeBody))
+	verr = assertProblem(t, err, "parameter_absent")
+	assert.Equal(t, []string{"oauth_body_hash"}, verr.Problem.ParametersAbsent)
+
+	v.BodyHash = BodyHashOptional
+	_, err = v.Verify(newOutcomeRequest(t, config, "", outcomeBody))
+	assert.Nil(t, err)
+}
+
+func TestVerifier_BodyHashSHA256(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret", Signer: HMACSHA256Signer{}}
+	// base64 of the SHA-256 digest of outcomeBody
+	req := newOutcomeRequest(t, config, "koJ2A8f3vm5dkOhfqeAloBmiiFFf3JSRs5csw4oBu04=", outcomeBody)
+	_, err := newTestVerifier().Verify(req)
+	assert.Nil(t, err)
+}
+
+func TestVerifier_BodyHashFormBody(t *testing.T) {
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer secret",
+		ExtraParams:    url.Values{"oauth_body_hash": {"2jmj7l5rSw0yVb/vlWAYkK/YBwk="}},
+	}
+	req, err := http.NewRequest("POST", "https://lms.example.com/outcomes", strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Nil(t, config.SignRequest(req, nil))
+	_, err = newTestVerifier().Verify(req)
+	assertProblem(t, err, "parameter_rejected")
+}
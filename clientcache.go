@@ -0,0 +1,137 @@
+package oauth1
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ktnyt/oauth1/internal"
+)
+
+// ClientCache builds and reuses one *http.Client per Token, so servers
+// acting on behalf of many users need not construct a client per request.
+// All cached clients share the base transport of the context's client and
+// thus its connection pool. The least recently used clients are evicted
+// beyond the cache size and clients expire after the TTL. A ClientCache is
+// safe for concurrent use.
+type ClientCache struct {
+	ctx    context.Context
+	config *Config
+	size   int
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	lru     *list.List
+	clients map[credentials]*list.Element
+	janitor internal.Janitor
+	now     func() time.Time
+}
+
+// credentials key cached clients by the credentials of their Token alone,
+// so that Tokens differing only in, say, their Expiry share a client.
+type credentials struct {
+	Token, TokenSecret string
+}
+
+type cachedClient struct {
+	key     credentials
+	client  *http.Client
+	expires time.Time
+}
+
+// NewClientCache returns a ClientCache holding at most size clients for
+// config, each for at most ttl. A size or ttl of zero means no limit.
+func NewClientCache(ctx context.Context, config *Config, size int, ttl time.Duration) *ClientCache {
+	return &ClientCache{
+		ctx:     ctx,
+		config:  config,
+		size:    size,
+		ttl:     ttl,
+		lru:     list.New(),
+		clients: make(map[credentials]*list.Element),
+	}
+}
+
+// Client returns the cached client for token, building it if needed.
+func (c *ClientCache) Client(token *Token) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock()
+	key := credentials{token.Token, token.TokenSecret}
+	if e, ok := c.clients[key]; ok {
+		cached := e.Value.(*cachedClient)
+		if c.ttl <= 0 || now.Before(cached.expires) {
+			c.lru.MoveToFront(e)
+			return cached.client
+		}
+		c.remove(e)
+	}
+	cached := &cachedClient{
+		key:     key,
+		client:  c.config.Client(c.ctx, token.Token, token.TokenSecret),
+		expires: now.Add(c.ttl),
+	}
+	c.clients[key] = c.lru.PushFront(cached)
+	if c.size > 0 && c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+	return cached.client
+}
+
+// Remove evicts the client for token, e.g. after the token was revoked.
+func (c *ClientCache) Remove(token *Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.clients[credentials{token.Token, token.TokenSecret}]; ok {
+		c.remove(e)
+	}
+}
+
+// Len returns the number of cached clients.
+func (c *ClientCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Prune evicts expired clients.
+func (c *ClientCache) Prune() {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock()
+	for e := c.lru.Back(); e != nil; {
+		prev := e.Prev()
+		if !now.Before(e.Value.(*cachedClient).expires) {
+			c.remove(e)
+		}
+		e = prev
+	}
+}
+
+// StartJanitor prunes expired clients every interval, or every minute if
+// interval is not positive, in a background goroutine until Stop is called.
+func (c *ClientCache) StartJanitor(interval time.Duration) {
+	c.janitor.Start(interval, c.Prune)
+}
+
+// Stop stops the janitor goroutine, if running.
+func (c *ClientCache) Stop() {
+	c.janitor.Stop()
+}
+
+func (c *ClientCache) remove(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.clients, e.Value.(*cachedClient).key)
+}
+
+func (c *ClientCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,83 @@
+package oauth1
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCache(t *testing.T) {
+	cache := NewClientCache(NoContext, &Config{ConsumerKey: "consumer_key"}, 2, 0)
+	alice := &Token{Token: "alice", TokenSecret: "alice_secret"}
+	bob := &Token{Token: "bob", TokenSecret: "bob_secret"}
+	carol := &Token{Token: "carol", TokenSecret: "carol_secret"}
+
+	aliceClient := cache.Client(alice)
+	assert.True(t, aliceClient == cache.Client(&Token{Token: "alice", TokenSecret: "alice_secret"}))
+	bobClient := cache.Client(bob)
+	assert.False(t, aliceClient == bobClient)
+
+	// alice was used more recently than bob, who is evicted for carol
+	cache.Client(alice)
+	cache.Client(carol)
+	assert.Equal(t, 2, cache.Len())
+	assert.True(t, aliceClient == cache.Client(alice))
+	assert.False(t, bobClient == cache.Client(bob))
+
+	cache.Remove(bob)
+	assert.Equal(t, 1, cache.Len())
+
+	// Tokens with equal credentials share a client whatever their Expiry
+	expiring := &Token{Token: "alice", TokenSecret: "alice_secret", Expiry: time.Now().Add(time.Hour)}
+	assert.True(t, aliceClient == cache.Client(expiring))
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestClientCache_SignsWithToken(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "alice", params["oauth_token"])
+	})
+	defer server.Close()
+
+	cache := NewClientCache(NoContext, &Config{ConsumerKey: "consumer_key"}, 0, 0)
+	_, err := cache.Client(&Token{Token: "alice", TokenSecret: "alice_secret"}).Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestClientCache_TTL(t *testing.T) {
+	now := time.Unix(1318467427, 0)
+	cache := NewClientCache(NoContext, &Config{ConsumerKey: "consumer_key"}, 0, time.Minute)
+	cache.now = func() time.Time { return now }
+	alice := &Token{Token: "alice"}
+	client := cache.Client(alice)
+	now = now.Add(30 * time.Second)
+	cache.Client(&Token{Token: "bob"})
+	assert.True(t, client == cache.Client(alice))
+
+	now = now.Add(30 * time.Second)
+	cache.Prune()
+	assert.Equal(t, 1, cache.Len())
+	assert.False(t, client == cache.Client(alice))
+}
+
+func TestClientCache_Concurrent(t *testing.T) {
+	cache := NewClientCache(NoContext, &Config{ConsumerKey: "consumer_key"}, 4, time.Nanosecond)
+	cache.StartJanitor(time.Millisecond)
+	defer cache.Stop()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cache.Client(&Token{Token: string(rune('a' + (i+j)%6))})
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.True(t, cache.Len() <= 4)
+}
@@ -0,0 +1,21 @@
+package oauth1
+
+import "time"
+
+// OffsetClock returns a clock running offset ahead of the local clock, to be
+// set as a Config's Clock when signing requests to a provider whose clock is
+// skewed, for example OffsetClock(ReadStats().ClockSkew).
+func OffsetClock(offset time.Duration) func() time.Time {
+	return func() time.Time {
+		return time.Now().Add(offset)
+	}
+}
+
+// signingTime returns the time from clock, or the local time if clock is
+// nil.
+func signingTime(clock func() time.Time) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock()
+}
@@ -0,0 +1,38 @@
+package oauth1
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetClock(t *testing.T) {
+	clock := OffsetClock(time.Hour)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), clock(), time.Second)
+}
+
+func TestConfigClient_Clock(t *testing.T) {
+	fixed := time.Unix(1318622958, 0)
+	var signatures []string
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "1318622958", params["oauth_timestamp"])
+		signatures = append(signatures, params["oauth_signature"])
+	})
+	defer server.Close()
+
+	config := NewConfig("consumer_key", "consumer_secret",
+		WithClock(func() time.Time { return fixed }),
+		WithNoncer(FixedNoncer("nonce")))
+	client := config.Client(NoContext, "token", "secret")
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(server.URL)
+		assert.Nil(t, err)
+	}
+	// fixed clock and nonce make signatures reproducible
+	if assert.Len(t, signatures, 2) {
+		assert.Equal(t, signatures[0], signatures[1])
+	}
+}
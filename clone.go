@@ -0,0 +1,93 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// Clone returns a deep copy of the Config. Mutable state, such as the
+// Endpoint Fallbacks, Header, ExtraParams, the Retry policy, the Routes and
+// Interceptors slices and the active endpoint selection, is copied so the clone can be
+// modified and used concurrently with the original without data races.
+// Immutable values (credentials, Context, HTTPClient, Metrics and the
+// Interceptor functions themselves) are shared.
+func (c *Config) Clone() *Config {
+	c2 := &Config{
+		Context:            c.Context,
+		HTTPClient:         c.HTTPClient,
+		Timeout:            c.Timeout,
+		ConsumerKey:        c.ConsumerKey,
+		ConsumerSecret:     c.ConsumerSecret,
+		SecretProvider:     c.SecretProvider,
+		CallbackURL:        c.CallbackURL,
+		Endpoint:           c.Endpoint.clone(),
+		Protocol:           c.Protocol,
+		Accept:             c.Accept,
+		ResponseParser:     c.ResponseParser,
+		Noncer:             c.Noncer,
+		Clock:              c.Clock,
+		Signer:             c.Signer,
+		Realm:              c.Realm,
+		ExcludeQueryParams: c.ExcludeQueryParams,
+		ExcludeBodyParams:  c.ExcludeBodyParams,
+		CompressRequests:   c.CompressRequests,
+		Metrics:            c.Metrics,
+		active:             atomic.LoadInt32(&c.active),
+	}
+	if c.Header != nil {
+		c2.Header = make(http.Header, len(c.Header))
+		for k, s := range c.Header {
+			c2.Header[k] = append([]string(nil), s...)
+		}
+	}
+	if c.ExtraParams != nil {
+		c2.ExtraParams = cloneValues(c.ExtraParams)
+	}
+	if c.Retry != nil {
+		retry := *c.Retry
+		c2.Retry = &retry
+	}
+	if c.Routes != nil {
+		c2.Routes = append([]Route(nil), c.Routes...)
+	}
+	if c.Interceptors != nil {
+		c2.Interceptors = append([]Interceptor(nil), c.Interceptors...)
+	}
+	return c2
+}
+
+func (e Endpoint) clone() Endpoint {
+	e2 := e
+	if e.Fallbacks != nil {
+		e2.Fallbacks = make([]Endpoint, len(e.Fallbacks))
+		for i := range e.Fallbacks {
+			e2.Fallbacks[i] = e.Fallbacks[i].clone()
+		}
+	}
+	return e2
+}
+
+// Clone returns a copy of the Transport sharing its credentials and Base
+// RoundTripper, which are never modified by the Transport, and copying its
+// Routes. The clone may be reconfigured, for example with a different Base,
+// and used concurrently with the original.
+func (t *Transport) Clone() *Transport {
+	t2 := *t
+	if t.Routes != nil {
+		t2.Routes = append([]Route(nil), t.Routes...)
+	}
+	if t.ExtraParams != nil {
+		t2.ExtraParams = cloneValues(t.ExtraParams)
+	}
+	return &t2
+}
+
+// cloneValues returns a deep copy of v.
+func cloneValues(v url.Values) url.Values {
+	v2 := make(url.Values, len(v))
+	for k, s := range v {
+		v2[k] = append([]string(nil), s...)
+	}
+	return v2
+}
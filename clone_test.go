@@ -0,0 +1,74 @@
+package oauth1
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigClone(t *testing.T) {
+	interceptor := func(next http.RoundTripper) http.RoundTripper { return next }
+	config := &Config{
+		Context:        NoContext,
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		CallbackURL:    "https://example.com/callback",
+		Endpoint: Endpoint{
+			RequestTokenURL: "https://example.com/request_token",
+			Fallbacks: []Endpoint{
+				{RequestTokenURL: "https://mirror.example.com/request_token"},
+			},
+		},
+		Interceptors: []Interceptor{interceptor},
+		Retry:        &RetryPolicy{MaxAttempts: 3},
+	}
+	config.active = 1
+
+	clone := config.Clone()
+	assert.Equal(t, config.ConsumerKey, clone.ConsumerKey)
+	assert.Equal(t, config.ConsumerSecret, clone.ConsumerSecret)
+	assert.Equal(t, config.CallbackURL, clone.CallbackURL)
+	assert.Equal(t, config.Endpoint, clone.Endpoint)
+	assert.Equal(t, config.Retry, clone.Retry)
+	assert.Len(t, clone.Interceptors, 1)
+	assert.Equal(t, "https://mirror.example.com/request_token", clone.activeEndpoint().RequestTokenURL)
+
+	// modifying the clone leaves the original untouched
+	clone.Endpoint.Fallbacks[0].RequestTokenURL = "https://other.example.com/request_token"
+	clone.Interceptors[0] = nil
+	clone.Retry.MaxAttempts = 5
+	assert.Equal(t, "https://mirror.example.com/request_token", config.Endpoint.Fallbacks[0].RequestTokenURL)
+	assert.NotNil(t, config.Interceptors[0])
+	assert.Equal(t, 3, config.Retry.MaxAttempts)
+}
+
+func TestConfigClone_Concurrent(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key"}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clone := config.Clone()
+			clone.ConsumerKey = "tenant_key"
+			clone.Endpoint.Fallbacks = append(clone.Endpoint.Fallbacks, Endpoint{})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "consumer_key", config.ConsumerKey)
+	assert.Empty(t, config.Endpoint.Fallbacks)
+}
+
+func TestTransportClone(t *testing.T) {
+	tr := &Transport{
+		consumerKey:    "consumer_key",
+		consumerSecret: "consumer_secret",
+		source:         StaticTokenSource(&Token{Token: "access_token", TokenSecret: "access_secret"}),
+	}
+	clone := tr.Clone()
+	assert.Equal(t, tr, clone)
+	clone.Base = &http.Transport{}
+	assert.Nil(t, tr.Base)
+}
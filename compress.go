@@ -0,0 +1,53 @@
+package oauth1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// compressBody replaces the body of req with its gzip compression read from
+// body and sets Content-Encoding accordingly. It is applied after signing,
+// so signatures cover the uncompressed form parameters.
+func compressBody(req *http.Request, body io.ReadCloser) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.Copy(zw, body)
+	body.Close()
+	if err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	compressed := buf.Bytes()
+	req.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	req.ContentLength = int64(len(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// streamCompressedBody is like compressBody but compresses body as it is
+// sent instead of buffering it, for bodies too large to hold in memory. The
+// request is sent with chunked encoding and cannot be replayed.
+func streamCompressedBody(req *http.Request, body io.ReadCloser) {
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, body)
+		body.Close()
+		if err == nil {
+			err = zw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	req.Body = pr
+	req.ContentLength = -1
+	req.GetBody = nil
+	req.Header.Set("Content-Encoding", "gzip")
+}
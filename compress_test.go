@@ -0,0 +1,90 @@
+package oauth1
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigClient_CompressRequests(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "gzip", req.Header.Get("Content-Encoding"))
+		// the uncompressed form is signed
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		expected := formSignature(t, signing{consumerKey: "consumer_key"}, req, url.Values{"status": {"hello"}}, "secret", params)
+		assert.Equal(t, expected, params["oauth_signature"])
+		zr, err := gzip.NewReader(req.Body)
+		if assert.Nil(t, err) {
+			body, err := ioutil.ReadAll(zr)
+			assert.Nil(t, err)
+			assert.Equal(t, "status=hello", string(body))
+		}
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", CompressRequests: true}
+	res, err := config.Client(NoContext, "token", "secret").PostForm(server.URL, url.Values{"status": {"hello"}})
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestConfigClient_CompressRequestsSkipsBodiless(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Empty(t, req.Header.Get("Content-Encoding"))
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", CompressRequests: true}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestConfigClient_CompressRequestsStreaming(t *testing.T) {
+	payload := strings.Repeat("chunk of a large upload\n", 1<<14)
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "gzip", req.Header.Get("Content-Encoding"))
+		assert.Equal(t, int64(-1), req.ContentLength)
+		zr, err := gzip.NewReader(req.Body)
+		if assert.Nil(t, err) {
+			body, err := ioutil.ReadAll(zr)
+			assert.Nil(t, err)
+			assert.Equal(t, payload, string(body))
+		}
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ExcludeBodyParams: true, CompressRequests: true}
+	res, err := config.Client(NoContext, "token", "secret").Post(server.URL, "application/octet-stream", strings.NewReader(payload))
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestTransport_CompressSkipsEmptyBodies(t *testing.T) {
+	tr := &Transport{
+		consumerKey: "consumer_key",
+		source:      StaticTokenSource(&Token{Token: "token", TokenSecret: "secret"}),
+		compress:    true,
+		Base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			assert.Empty(t, req.Header.Get("Content-Encoding"))
+			assert.Equal(t, int64(0), req.ContentLength)
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+	req, err := http.NewRequest("GET", "http://example.com", http.NoBody)
+	assert.Nil(t, err)
+	_, err = tr.RoundTrip(req)
+	assert.Nil(t, err)
+
+	req, err = http.NewRequest("POST", "http://example.com", nil)
+	assert.Nil(t, err)
+	req.Body = ioutil.NopCloser(strings.NewReader(""))
+	_, err = tr.RoundTrip(req)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,48 @@
+package oauth1
+
+import (
+	"crypto/rsa"
+	"errors"
+)
+
+// ErrConsumerRefused is returned by a ConsumerStore for consumers which are
+// temporarily not accepted, e.g. because they exceeded a rate limit.
+// Verifiers report it as consumer_key_refused.
+var ErrConsumerRefused = errors.New("oauth1: consumer refused")
+
+// Consumer is a consumer registered with a provider.
+type Consumer struct {
+	Key    string
+	Secret string
+
+	// PublicKey verifies the signatures of consumers signing with RSA-SHA1
+	// or RSA-SHA256, which are rejected if nil
+	PublicKey *rsa.PublicKey
+
+	// Disabled consumers are permanently refused with
+	// consumer_key_rejected
+	Disabled bool
+}
+
+// ConsumerStore looks up the consumers registered with a provider by
+// consumer key. Implementations must be safe for concurrent use.
+type ConsumerStore interface {
+	// Consumer returns the consumer of consumerKey. It returns
+	// ErrConsumerRefused for consumers to be refused temporarily and
+	// another error if the consumer is unknown.
+	Consumer(consumerKey string) (*Consumer, error)
+}
+
+// ConsumerSecretStore is a ConsumerStore looking up consumer secrets with a
+// function, such as the ConsumerSecret of a Verifier.
+type ConsumerSecretStore func(consumerKey string) (string, error)
+
+// Consumer returns the consumer of consumerKey with the secret returned by
+// f.
+func (f ConsumerSecretStore) Consumer(consumerKey string) (*Consumer, error) {
+	secret, err := f(consumerKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{Key: consumerKey, Secret: secret}, nil
+}
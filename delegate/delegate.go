@@ -0,0 +1,236 @@
+// Package delegate implements a delegated OAuth1 signing service and a
+// client-side RoundTripper using it, so consumer secrets can live on a
+// hardened host instead of every application server.
+//
+// Application servers describe each outgoing request to the service, which
+// verifies the caller, signs the description with its Config and answers
+// with the Authorization header to attach.
+package delegate
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// maxRequestSize limits the size of sign requests accepted by a Handler.
+const maxRequestSize = 1 << 20
+
+// SignRequest describes a request to be signed by the service.
+type SignRequest struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type,omitempty"`
+
+	// Body of application/x-www-form-urlencoded requests, whose parameters
+	// are signed; other bodies are never sent to the service
+	Body []byte `json:"body,omitempty"`
+
+	// Token credentials the request is signed with
+	Token       string `json:"oauth_token"`
+	TokenSecret string `json:"oauth_token_secret"`
+}
+
+// SignResponse carries the Authorization header for a SignRequest.
+type SignResponse struct {
+	Authorization string `json:"authorization"`
+}
+
+// errParamsInQuery is returned for Configs whose Endpoint transmits the
+// protocol parameters in the query rather than an Authorization header.
+var errParamsInQuery = errors.New("delegate: Config's Endpoint has ParamsInQuery, which cannot be signed into an Authorization header")
+
+// Handler is the signing service. It answers POSTed JSON SignRequests with
+// a JSON SignResponse. Configs whose Endpoint has ParamsInQuery set are not
+// supported, as the service answers with an Authorization header only, and
+// their requests are answered with 500 Internal Server Error.
+type Handler struct {
+	// Config holding the consumer credentials requests are signed with
+	Config *oauth1.Config
+
+	// Authenticate verifies the caller; unauthenticated requests are
+	// answered with 401 Unauthorized
+	Authenticate func(*http.Request) bool
+}
+
+// BearerAuth returns an authenticator accepting requests which carry the
+// given bearer token, compared in constant time.
+func BearerAuth(token string) func(*http.Request) bool {
+	expected := []byte("Bearer " + token)
+	return func(req *http.Request) bool {
+		return subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) == 1
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Authenticate == nil || !h.Authenticate(req) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if h.Config.Endpoint.ParamsInQuery {
+		http.Error(w, errParamsInQuery.Error(), http.StatusInternalServerError)
+		return
+	}
+	var signReq SignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxRequestSize)).Decode(&signReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	authorization, err := h.sign(&signReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SignResponse{Authorization: authorization})
+}
+
+func (h *Handler) sign(signReq *SignRequest) (string, error) {
+	req, err := http.NewRequest(signReq.Method, signReq.URL, bytes.NewReader(signReq.Body))
+	if err != nil {
+		return "", err
+	}
+	if signReq.ContentType != "" {
+		req.Header.Set("Content-Type", signReq.ContentType)
+	}
+	token := &oauth1.Token{Token: signReq.Token, TokenSecret: signReq.TokenSecret}
+	if err := h.Config.SignRequest(req, token); err != nil {
+		return "", err
+	}
+	return req.Header.Get("Authorization"), nil
+}
+
+// Transport is an http.RoundTripper which obtains the Authorization header
+// of each request from a signing service and then sends the request with
+// Base.
+type Transport struct {
+	// ServiceURL of the signing Handler
+	ServiceURL string
+
+	// BearerToken authenticating this client to the service
+	BearerToken string
+
+	// Token credentials requests are signed with
+	Token       string
+	TokenSecret string
+
+	// Client used to reach the service, http.DefaultClient if nil
+	Client *http.Client
+
+	// Base is the base RoundTripper used to make HTTP requests. If nil, then
+	// http.DefaultTransport is used
+	Base http.RoundTripper
+}
+
+// RoundTrip signs the request using the service and sends it. The request
+// is not modified: the header is set on a clone and a form body, which must
+// be read to be signed, is read from GetBody if set and replaced on the
+// clone only. The service is called with the request's context.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := new(http.Request)
+	*req2 = *req
+	req2.Header = make(http.Header, len(req.Header)+1)
+	for k, s := range req.Header {
+		req2.Header[k] = append([]string(nil), s...)
+	}
+	signReq := &SignRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		ContentType: req.Header.Get("Content-Type"),
+		Token:       t.Token,
+		TokenSecret: t.TokenSecret,
+	}
+	if req.Body != nil && isForm(signReq.ContentType) {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		signReq.Body = body
+		req2.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req2.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	authorization, err := t.sign(req.Context(), signReq)
+	if err != nil {
+		return nil, err
+	}
+	req2.Header.Set("Authorization", authorization)
+	return t.base().RoundTrip(req2)
+}
+
+// readBody reads the body of req, from a fresh copy if req has a GetBody so
+// that req.Body is left unread.
+func readBody(req *http.Request) ([]byte, error) {
+	body := req.Body
+	if req.GetBody != nil {
+		var err error
+		if body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
+func (t *Transport) sign(ctx context.Context, signReq *SignRequest) (string, error) {
+	b, err := json.Marshal(signReq)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", t.ServiceURL, bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+t.BearerToken)
+	}
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return "", fmt.Errorf("delegate: Signing service returned status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var signRes SignResponse
+	if err := json.NewDecoder(res.Body).Decode(&signRes); err != nil {
+		return "", err
+	}
+	if signRes.Authorization == "" {
+		return "", errors.New("delegate: Signing service returned no Authorization")
+	}
+	return signRes.Authorization, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func isForm(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
@@ -0,0 +1,147 @@
+package delegate
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ktnyt/oauth1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSigningService() *httptest.Server {
+	return httptest.NewServer(&Handler{
+		Config: &oauth1.Config{
+			ConsumerKey:    "consumer_key",
+			ConsumerSecret: "consumer_secret",
+		},
+		Authenticate: BearerAuth("caller_secret"),
+	})
+}
+
+func TestTransport(t *testing.T) {
+	service := newSigningService()
+	defer service.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization := req.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(authorization, "OAuth "))
+		assert.Contains(t, authorization, `oauth_consumer_key="consumer_key"`)
+		assert.Contains(t, authorization, `oauth_token="token"`)
+		assert.NotContains(t, authorization, `status="hello"`)
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "status=hello", string(body))
+	}))
+	defer api.Close()
+
+	client := &http.Client{Transport: &Transport{
+		ServiceURL:  service.URL,
+		BearerToken: "caller_secret",
+		Token:       "token",
+		TokenSecret: "token_secret",
+	}}
+	res, err := client.PostForm(api.URL, url.Values{"status": {"hello"}})
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestTransport_Unauthenticated(t *testing.T) {
+	service := newSigningService()
+	defer service.Close()
+
+	client := &http.Client{Transport: &Transport{
+		ServiceURL:  service.URL,
+		BearerToken: "wrong_secret",
+		Token:       "token",
+	}}
+	_, err := client.Get("http://api.example.com/")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "delegate: Signing service returned status 401")
+	}
+}
+
+func TestHandler_BadRequest(t *testing.T) {
+	handler := &Handler{
+		Config:       &oauth1.Config{ConsumerKey: "consumer_key"},
+		Authenticate: func(*http.Request) bool { return true },
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader("not json")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTransport_doesNotModifyRequest(t *testing.T) {
+	service := newSigningService()
+	defer service.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "status=hello", string(body))
+	}))
+	defer api.Close()
+
+	tr := &Transport{
+		ServiceURL:  service.URL,
+		BearerToken: "caller_secret",
+		Token:       "token",
+		TokenSecret: "token_secret",
+	}
+	req, err := http.NewRequest("POST", api.URL, strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := tr.RoundTrip(req)
+	if assert.Nil(t, err) {
+		res.Body.Close()
+	}
+	assert.Empty(t, req.Header.Get("Authorization"))
+	// the body of req is left unread
+	body, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "status=hello", string(body))
+}
+
+func TestTransport_Context(t *testing.T) {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("expected the signing service not to be called")
+	}))
+	defer service.Close()
+
+	tr := &Transport{ServiceURL: service.URL, Token: "token"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequest("GET", "http://api.example.com/", nil)
+	assert.Nil(t, err)
+	_, err = tr.RoundTrip(req.WithContext(ctx))
+	assert.Error(t, err)
+}
+
+func TestHandler_ParamsInQuery(t *testing.T) {
+	service := httptest.NewServer(&Handler{
+		Config: &oauth1.Config{
+			ConsumerKey:    "consumer_key",
+			ConsumerSecret: "consumer_secret",
+			Endpoint:       oauth1.Endpoint{ParamsInQuery: true},
+		},
+		Authenticate: BearerAuth("caller_secret"),
+	})
+	defer service.Close()
+
+	client := &http.Client{Transport: &Transport{
+		ServiceURL:  service.URL,
+		BearerToken: "caller_secret",
+		Token:       "token",
+		TokenSecret: "token_secret",
+	}}
+	_, err := client.Get("http://api.example.com/measure")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "delegate: Signing service returned status 500")
+		assert.Contains(t, err.Error(), "ParamsInQuery")
+	}
+}
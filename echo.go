@@ -0,0 +1,166 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ktnyt/oauth1/internal"
+)
+
+// OAuth Echo headers, see https://developer.twitter.com/en/docs/authentication/oauth-echo
+const (
+	// EchoProviderHeader names the provider URL verifying the credentials
+	EchoProviderHeader = "X-Auth-Service-Provider"
+
+	// EchoAuthorizationHeader carries the Authorization header signed for
+	// the provider URL
+	EchoAuthorizationHeader = "X-Verify-Credentials-Authorization"
+)
+
+var (
+	// ErrEchoHeadersMissing is returned when a request lacks the OAuth Echo
+	// headers.
+	ErrEchoHeadersMissing = errors.New("oauth1: Request missing " + EchoProviderHeader + " or " + EchoAuthorizationHeader)
+
+	// ErrEchoProviderNotAllowed is returned when a request names a provider
+	// URL the EchoVerifier does not allow.
+	ErrEchoProviderNotAllowed = errors.New("oauth1: " + EchoProviderHeader + " is not an allowed provider")
+)
+
+// EchoError is returned when the provider refuses delegated credentials.
+type EchoError struct {
+	// Provider URL which was called
+	Provider string
+
+	// StatusCode and Body of the provider's response
+	StatusCode int
+	Body       []byte
+}
+
+func (e *EchoError) Error() string {
+	return fmt.Sprintf("oauth1: %s refused delegated credentials with status %d", e.Provider, e.StatusCode)
+}
+
+// EchoIdentity is an identity verified through OAuth Echo.
+type EchoIdentity struct {
+	// Provider URL which verified the credentials
+	Provider string
+
+	// Body of the provider's response, typically a JSON user object
+	Body []byte
+}
+
+// EchoVerifier verifies OAuth Echo credentials delegated to a service: it
+// replays the signed verification call described by the request's
+// X-Auth-Service-Provider and X-Verify-Credentials-Authorization headers
+// to the provider and returns the identity the provider vouches for.
+type EchoVerifier struct {
+	// AllowedProviders lists the provider URLs which may be named in
+	// X-Auth-Service-Provider, so clients cannot make the service call
+	// arbitrary URLs
+	AllowedProviders []string
+
+	// Client making verification calls, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// Verify checks the delegated credentials of req with the provider.
+func (v *EchoVerifier) Verify(req *http.Request) (*EchoIdentity, error) {
+	provider := req.Header.Get(EchoProviderHeader)
+	authorization := req.Header.Get(EchoAuthorizationHeader)
+	if provider == "" || authorization == "" {
+		return nil, ErrEchoHeadersMissing
+	}
+	allowed := false
+	for _, p := range v.AllowedProviders {
+		if p == provider {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, ErrEchoProviderNotAllowed
+	}
+
+	verification, err := http.NewRequest("GET", provider, nil)
+	if err != nil {
+		return nil, err
+	}
+	verification.Header.Set("Authorization", authorization)
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(verification.WithContext(req.Context()))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, &EchoError{Provider: provider, StatusCode: res.StatusCode, Body: body}
+	}
+	return &EchoIdentity{Provider: provider, Body: body}, nil
+}
+
+// EchoHeaders returns the OAuth Echo headers delegating token's credentials
+// to a service: the provider URL and an Authorization header signed for a
+// GET request to it, which the service replays to verify the user.
+func (c *Config) EchoHeaders(providerURL string, token *Token) (http.Header, error) {
+	verification, err := http.NewRequest("GET", providerURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SignRequest(verification, token); err != nil {
+		return nil, err
+	}
+	header := make(http.Header)
+	header.Set(EchoProviderHeader, providerURL)
+	header.Set(EchoAuthorizationHeader, verification.Header.Get("Authorization"))
+	return header, nil
+}
+
+// EchoClient returns an HTTP client which adds freshly signed OAuth Echo
+// headers for providerURL to each request, for calling services, such as
+// media hosts, which verify the user with the provider. The requests
+// themselves are not signed.
+// HTTP transport will be obtained using the provided context.
+// The returned client and its Transport should not be modified.
+func (c *Config) EchoClient(ctx context.Context, providerURL string, token *Token) *http.Client {
+	return &http.Client{Transport: &echoTransport{
+		config:   c,
+		provider: providerURL,
+		token:    token,
+		base:     internal.ContextClient(ctx).Transport,
+	}}
+}
+
+// echoTransport adds OAuth Echo headers to requests.
+type echoTransport struct {
+	config   *Config
+	provider string
+	token    *Token
+	base     http.RoundTripper
+}
+
+func (t *echoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	header, err := t.config.EchoHeaders(t.provider, t.token)
+	if err != nil {
+		return nil, err
+	}
+	req2 := cloneRequest(req)
+	for k, v := range header {
+		req2.Header[k] = v
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req2)
+}
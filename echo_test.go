@@ -0,0 +1,90 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEchoVerifier(t *testing.T) {
+	provider := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != `OAuth oauth_token="valid"` {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"code":32}]}`))
+			return
+		}
+		w.Write([]byte(`{"screen_name":"gopher"}`))
+	})
+	defer provider.Close()
+	verifier := &EchoVerifier{AllowedProviders: []string{provider.URL}}
+
+	req := httptest.NewRequest("POST", "/upload", nil)
+	req.Header.Set(EchoProviderHeader, provider.URL)
+	req.Header.Set(EchoAuthorizationHeader, `OAuth oauth_token="valid"`)
+	identity, err := verifier.Verify(req)
+	assert.Nil(t, err)
+	assert.Equal(t, provider.URL, identity.Provider)
+	assert.Equal(t, `{"screen_name":"gopher"}`, string(identity.Body))
+
+	req.Header.Set(EchoAuthorizationHeader, `OAuth oauth_token="invalid"`)
+	_, err = verifier.Verify(req)
+	if echoErr, ok := err.(*EchoError); assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnauthorized, echoErr.StatusCode)
+		assert.Equal(t, `{"errors":[{"code":32}]}`, string(echoErr.Body))
+	}
+}
+
+func TestEchoVerifier_Refused(t *testing.T) {
+	verifier := &EchoVerifier{AllowedProviders: []string{"https://api.twitter.com/1.1/account/verify_credentials.json"}}
+
+	req := httptest.NewRequest("POST", "/upload", nil)
+	_, err := verifier.Verify(req)
+	assert.Equal(t, ErrEchoHeadersMissing, err)
+
+	req.Header.Set(EchoProviderHeader, "http://169.254.169.254/latest/meta-data")
+	req.Header.Set(EchoAuthorizationHeader, `OAuth oauth_token="token"`)
+	_, err = verifier.Verify(req)
+	assert.Equal(t, ErrEchoProviderNotAllowed, err)
+}
+
+func TestConfigEchoClient(t *testing.T) {
+	provider := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		if params["oauth_token"] != "access_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"screen_name":"gopher"}`))
+	})
+	defer provider.Close()
+	providerURL := provider.URL + "/1.1/account/verify_credentials.json"
+	verifier := &EchoVerifier{AllowedProviders: []string{providerURL}}
+
+	service := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Empty(t, req.Header.Get("Authorization"))
+		identity, err := verifier.Verify(req)
+		if assert.Nil(t, err) {
+			assert.Equal(t, `{"screen_name":"gopher"}`, string(identity.Body))
+		}
+	})
+	defer service.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	token := &Token{Token: "access_token", TokenSecret: "access_secret"}
+	res, err := config.EchoClient(NoContext, providerURL, token).Post(service.URL+"/upload", "image/png", nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestConfigEchoHeaders(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key"}
+	header, err := config.EchoHeaders("https://api.twitter.com/1.1/account/verify_credentials.json", &Token{Token: "access_token"})
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.twitter.com/1.1/account/verify_credentials.json", header.Get(EchoProviderHeader))
+	params := parseOAuthParamsOrFail(t, header.Get(EchoAuthorizationHeader))
+	assert.Equal(t, "access_token", params["oauth_token"])
+	assert.NotEmpty(t, params["oauth_signature"])
+}
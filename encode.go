@@ -0,0 +1,102 @@
+package oauth1
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// percentEncode encodes s per RFC 5849 3.6: every byte outside the
+// unreserved set A-Z a-z 0-9 - . _ ~ is encoded as % followed by two
+// uppercase hex digits. Unlike url.QueryEscape, spaces become %20.
+func percentEncode(s string) string {
+	const hex = "0123456789ABCDEF"
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if !unreserved(s[i]) {
+			n++
+		}
+	}
+	if n == 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 2*n)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if unreserved(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
+
+// unreserved reports whether c is an RFC 3986 unreserved character.
+func unreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// upperPercentEncoding returns s with the hex digits of its percent encoded
+// octets uppercased, so "%e3%81%82" becomes "%E3%81%82".
+func upperPercentEncoding(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	b := []byte(s)
+	for i := 0; i+2 < len(b); i++ {
+		if b[i] == '%' {
+			b[i+1] = upperHex(b[i+1])
+			b[i+2] = upperHex(b[i+2])
+			i += 2
+		}
+	}
+	return string(b)
+}
+
+func upperHex(c byte) byte {
+	if 'a' <= c && c <= 'f' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// encodedPair is a percent encoded parameter name and value.
+type encodedPair struct {
+	name, value string
+}
+
+// encodePairs percent encodes params into pairs sorted by encoded name and
+// then, for repeated names, by encoded value per RFC 5849 3.4.1.3.2.
+// Parameters without a value, whether sent as "name=" or "name", have an
+// empty value.
+func encodePairs(params url.Values) []encodedPair {
+	var pairs []encodedPair
+	for name, values := range params {
+		for _, value := range values {
+			pairs = append(pairs, encodedPair{percentEncode(name), percentEncode(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].name != pairs[j].name {
+			return pairs[i].name < pairs[j].name
+		}
+		return pairs[i].value < pairs[j].value
+	})
+	return pairs
+}
+
+// encodeParams returns the encoded pairs of params as "name=value" joined by
+// "&".
+func encodeParams(params url.Values) string {
+	pairs := encodePairs(params)
+	joined := make([]string, len(pairs))
+	for i, p := range pairs {
+		joined[i] = p.name + "=" + p.value
+	}
+	return strings.Join(joined, "&")
+}
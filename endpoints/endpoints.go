@@ -0,0 +1,39 @@
+// Package endpoints provides the Endpoints of popular OAuth1 providers.
+//
+// Each preset is tested against synthetic token endpoint responses, shaped
+// like the ones the provider documents rather than recorded from it.
+package endpoints
+
+import (
+	"strings"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// Discogs is the Endpoint of the Discogs API.
+var Discogs = oauth1.Endpoint{
+	RequestTokenURL: "https://api.discogs.com/oauth/request_token",
+	AuthorizeURL:    "https://www.discogs.com/oauth/authorize",
+	AccessTokenURL:  "https://api.discogs.com/oauth/access_token",
+}
+
+// OpenStreetMap is the Endpoint of the OpenStreetMap API.
+var OpenStreetMap = oauth1.Endpoint{
+	RequestTokenURL: "https://www.openstreetmap.org/oauth/request_token",
+	AuthorizeURL:    "https://www.openstreetmap.org/oauth/authorize",
+	AccessTokenURL:  "https://www.openstreetmap.org/oauth/access_token",
+}
+
+// Jira returns the Endpoint of the self-hosted Jira instance at baseURL,
+// such as "https://jira.example.com". Jira application links sign with
+// RSA-SHA1, so Configs need an oauth1.RSASigner holding the consumer's
+// private key.
+func Jira(baseURL string) oauth1.Endpoint {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return oauth1.Endpoint{
+		RequestTokenURL: baseURL + "/plugins/servlet/oauth/request-token",
+		AuthorizeURL:    baseURL + "/plugins/servlet/oauth/authorize",
+		AccessTokenURL:  baseURL + "/plugins/servlet/oauth/access-token",
+		SignatureMethod: "RSA-SHA1",
+	}
+}
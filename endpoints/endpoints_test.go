@@ -0,0 +1,308 @@
+package endpoints
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ktnyt/oauth1"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	requestTokenResponse = "oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"
+	accessTokenResponse  = "oauth_token=access_token&oauth_token_secret=access_secret"
+)
+
+// tokenTransport answers token requests to the URLs of endpoint with
+// synthetic responses, shaped like those documented by the provider.
+// Requests carrying an oauth_token are token requests, which tells them
+// apart from temporary credential requests sent to the same URL.
+type tokenTransport struct {
+	endpoint     oauth1.Endpoint
+	requestToken string
+	accessToken  string
+	requests     []*http.Request
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	u := *req.URL
+	u.RawQuery = ""
+	var body string
+	switch {
+	case u.String() == t.endpoint.AccessTokenURL && protocolParams(req).Get("oauth_token") != "":
+		body = t.accessToken
+	case u.String() == t.endpoint.RequestTokenURL:
+		body = t.requestToken
+	default:
+		return nil, fmt.Errorf("unexpected request to %s", req.URL)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// protocolParams returns the protocol parameters of req, transmitted in its
+// Authorization header or else its query.
+func protocolParams(req *http.Request) url.Values {
+	if params, err := oauth1.ParseOAuthHeader(req.Header.Get("Authorization")); err == nil {
+		return params
+	}
+	return req.URL.Query()
+}
+
+func TestEndpoints(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	hmac := func(endpoint oauth1.Endpoint) *oauth1.Config {
+		return &oauth1.Config{ConsumerKey: "key", ConsumerSecret: "secret", CallbackURL: "https://app.example.com/callback", Endpoint: endpoint}
+	}
+	cases := []struct {
+		name          string
+		config        *oauth1.Config
+		requestParams url.Values
+		requestToken  string
+		accessToken   string
+		// want are parameters expected in the access token response
+		want url.Values
+	}{
+		{name: "Discogs", config: hmac(Discogs)},
+		{name: "OpenStreetMap", config: hmac(OpenStreetMap)},
+		{
+			name:        "Jira",
+			config:      &oauth1.Config{ConsumerKey: "key", CallbackURL: "oob", Endpoint: Jira("https://jira.example.com"), Signer: oauth1.RSASigner{PrivateKey: key}},
+			accessToken: accessTokenResponse + "&oauth_expires_in=157680000&oauth_session_handle=session_handle",
+			want:        url.Values{"oauth_session_handle": {"session_handle"}},
+		},
+		{
+			name:        "Twitter",
+			config:      hmac(Twitter),
+			accessToken: accessTokenResponse + "&user_id=6253282&screen_name=twitterapi",
+			want:        url.Values{"user_id": {"6253282"}, "screen_name": {"twitterapi"}},
+		},
+		{name: "Tumblr", config: hmac(Tumblr)},
+		{
+			name:        "Flickr",
+			config:      hmac(Flickr),
+			accessToken: "fullname=Jamal%20Fanaian&" + accessTokenResponse + "&user_nsid=21207597%40N07&username=jamalfanaian",
+			want:        url.Values{"user_nsid": {"21207597@N07"}},
+		},
+		{name: "Trello", config: hmac(Trello)},
+		{
+			name:          "Etsy",
+			config:        hmac(Etsy),
+			requestParams: EtsyScopes("email_r", "listings_r"),
+			requestToken:  "login_url=https%3A%2F%2Fwww.etsy.com%2Foauth%2Fsignin%3Foauth_consumer_key%3Dkey%26oauth_token%3Drequest_token&" + requestTokenResponse,
+		},
+		{
+			name:         "Evernote",
+			config:       hmac(Evernote(true)),
+			requestToken: "oauth_token=request_token&oauth_token_secret=",
+			accessToken:  "oauth_token=access_token&oauth_token_secret=&edam_shard=s4&edam_userId=161&edam_noteStoreUrl=https%3A%2F%2Fsandbox.evernote.com%2Fshard%2Fs4%2Fnotestore",
+			want:         url.Values{"edam_noteStoreUrl": {"https://sandbox.evernote.com/shard/s4/notestore"}},
+		},
+		{name: "Garmin", config: hmac(Garmin)},
+		{
+			name:        "Withings",
+			config:      hmac(Withings),
+			accessToken: accessTokenResponse + "&userid=1234567&deviceid=0",
+			want:        url.Values{"userid": {"1234567"}},
+		},
+		{
+			name:        "XeroPartner",
+			config:      XeroPartner("key", key, "https://app.example.com/callback"),
+			accessToken: accessTokenResponse + "&oauth_expires_in=1800&oauth_session_handle=session_handle&xero_org_muid=org",
+			want:        url.Values{"oauth_session_handle": {"session_handle"}, "xero_org_muid": {"org"}},
+		},
+		{name: "SmugMug", config: hmac(SmugMug)},
+	}
+	for _, c := range cases {
+		transport := &tokenTransport{endpoint: c.config.Endpoint, requestToken: requestTokenResponse, accessToken: accessTokenResponse}
+		if c.requestToken != "" {
+			transport.requestToken = c.requestToken
+		}
+		if c.accessToken != "" {
+			transport.accessToken = c.accessToken
+		}
+		config := c.config
+		config.HTTPClient = &http.Client{Transport: transport}
+
+		requestToken, requestSecret, err := config.RequestTokenWithParams(c.requestParams)
+		assert.Nil(t, err, c.name)
+		assert.Equal(t, "request_token", requestToken, c.name)
+		authorizationURL, err := config.AuthorizationURL(requestToken)
+		assert.Nil(t, err, c.name)
+		assert.True(t, strings.HasPrefix(authorizationURL.String(), config.Endpoint.AuthorizeURL+"?"), c.name)
+		assert.Equal(t, requestToken, authorizationURL.Query().Get("oauth_token"), c.name)
+
+		token, values, err := config.AccessTokenResponse(requestToken, requestSecret, "verifier")
+		assert.Nil(t, err, c.name)
+		if assert.NotNil(t, token, c.name) {
+			assert.Equal(t, "access_token", token.Token, c.name)
+		}
+		for name := range c.want {
+			assert.Equal(t, c.want[name], values[name], c.name)
+		}
+
+		if !assert.Len(t, transport.requests, 2, c.name) {
+			continue
+		}
+		for _, req := range transport.requests {
+			assert.Equal(t, "POST", req.Method, c.name)
+			if config.Endpoint.ParamsInQuery {
+				assert.Empty(t, req.Header.Get("Authorization"), c.name)
+			}
+			assert.NotEmpty(t, protocolParams(req).Get("oauth_signature"), c.name)
+		}
+		requestTokenReq, accessTokenReq := transport.requests[0], transport.requests[1]
+		assert.Equal(t, config.CallbackURL, protocolParams(requestTokenReq).Get("oauth_callback"), c.name)
+		for name := range c.requestParams {
+			assert.Equal(t, c.requestParams[name], requestTokenReq.URL.Query()[name], c.name)
+		}
+		assert.Equal(t, "verifier", protocolParams(accessTokenReq).Get("oauth_verifier"), c.name)
+	}
+}
+
+func TestJira(t *testing.T) {
+	assert.Equal(t, Jira("https://jira.example.com"), Jira("https://jira.example.com/"))
+	assert.Equal(t, "RSA-SHA1", Jira("https://jira.example.com").SignatureMethod)
+}
+
+func TestTwitter(t *testing.T) {
+	config := &oauth1.Config{Endpoint: Twitter}
+	authenticationURL, err := config.AuthenticationURL("request_token")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.twitter.com/oauth/authenticate?oauth_token=request_token", authenticationURL.String())
+
+	callback := httptest.NewRequest("GET", "https://app.example.com/callback?denied=request_token", nil)
+	_, _, err = oauth1.ParseAuthorizationCallback(callback)
+	assert.Equal(t, oauth1.ErrMissingVerifier, err)
+	assert.Equal(t, "request_token", callback.FormValue(TwitterDenied))
+}
+
+func TestFlickrPerms(t *testing.T) {
+	config := &oauth1.Config{Endpoint: Flickr}
+	authorizationURL, err := config.AuthorizationURLWithParams("request_token", FlickrWrite.AuthorizeParams())
+	assert.Nil(t, err)
+	assert.Equal(t, "write", authorizationURL.Query().Get("perms"))
+	assert.Equal(t, "request_token", authorizationURL.Query().Get("oauth_token"))
+}
+
+func TestTrelloAuthorizeParams(t *testing.T) {
+	config := &oauth1.Config{Endpoint: Trello}
+	params := TrelloAuthorizeParams(TrelloScope("read", "write"), TrelloExpiration("never"), TrelloName("My App"))
+	authorizationURL, err := config.AuthorizationURLWithParams("request_token", params)
+	assert.Nil(t, err)
+	query := authorizationURL.Query()
+	assert.Equal(t, "read,write", query.Get("scope"))
+	assert.Equal(t, "never", query.Get("expiration"))
+	assert.Equal(t, "My App", query.Get("name"))
+	assert.Equal(t, "request_token", query.Get("oauth_token"))
+}
+
+func TestEvernote(t *testing.T) {
+	assert.Equal(t, "https://www.evernote.com/oauth", Evernote(false).RequestTokenURL)
+	assert.Equal(t, "https://sandbox.evernote.com/oauth", Evernote(true).RequestTokenURL)
+}
+
+func TestSmugMugAuthorizeParams(t *testing.T) {
+	config := &oauth1.Config{Endpoint: SmugMug}
+	authorizationURL, err := config.AuthorizationURLWithParams("request_token", SmugMugAuthorizeParams(SmugMugFull, SmugMugModify))
+	assert.Nil(t, err)
+	query := authorizationURL.Query()
+	assert.Equal(t, "Full", query.Get("Access"))
+	assert.Equal(t, "Modify", query.Get("Permissions"))
+	assert.Equal(t, "request_token", query.Get("oauth_token"))
+}
+
+type consumerStore map[string]*oauth1.Consumer
+
+func (s consumerStore) Consumer(consumerKey string) (*oauth1.Consumer, error) {
+	consumer, ok := s[consumerKey]
+	if !ok {
+		return nil, fmt.Errorf("unknown consumer %s", consumerKey)
+	}
+	return consumer, nil
+}
+
+func TestXeroPrivateClient(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	v := &oauth1.Verifier{
+		Consumers:   consumerStore{"key": {Key: "key", PublicKey: &key.PublicKey}},
+		TokenSecret: func(token string) (string, error) { return "", nil },
+	}
+	var verified url.Values
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		verified, verifyErr = v.Verify(req)
+	}))
+	defer server.Close()
+
+	client := XeroPrivateClient(context.Background(), "key", key)
+	_, err = client.Get(server.URL + "/api.xro/2.0/Invoices")
+	assert.Nil(t, err)
+	assert.Nil(t, verifyErr)
+	assert.Equal(t, "key", verified.Get("oauth_token"))
+	assert.Equal(t, "RSA-SHA1", verified.Get("oauth_signature_method"))
+}
+
+func TestXeroPartner_Refresh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	config := XeroPartner("key", key, "https://app.example.com/callback")
+	transport := &tokenTransport{
+		endpoint:    config.Endpoint,
+		accessToken: "oauth_token=refreshed_token&oauth_token_secret=refreshed_secret&oauth_expires_in=1800",
+	}
+	config.HTTPClient = &http.Client{Transport: transport}
+
+	refreshed, err := config.RefreshToken("access_token", "access_secret", "session_handle")
+	assert.Nil(t, err)
+	if assert.NotNil(t, refreshed) {
+		assert.Equal(t, "refreshed_token", refreshed.Token)
+		assert.Equal(t, "session_handle", refreshed.SessionHandle)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), refreshed.Expiry, time.Minute)
+	}
+	if assert.Len(t, transport.requests, 1) {
+		params := protocolParams(transport.requests[0])
+		assert.Equal(t, "session_handle", params.Get("oauth_session_handle"))
+		assert.Equal(t, "RSA-SHA1", params.Get("oauth_signature_method"))
+	}
+}
+
+func TestNetSuite(t *testing.T) {
+	v := &oauth1.Verifier{
+		ConsumerSecret: func(consumerKey string) (string, error) { return "consumer_secret", nil },
+		TokenSecret:    func(token string) (string, error) { return "token_secret", nil },
+	}
+	var authorization string
+	var verified url.Values
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+		verified, verifyErr = v.Verify(req)
+	}))
+	defer server.Close()
+
+	config := NetSuite("1234567_sb1", "consumer_key", "consumer_secret")
+	client := config.Client(context.Background(), "token_id", "token_secret")
+	_, err := client.Get(server.URL + "/services/rest/record/v1/customer?limit=5")
+	assert.Nil(t, err)
+	assert.Nil(t, verifyErr)
+	assert.True(t, strings.HasPrefix(authorization, `OAuth realm="1234567_SB1"`), authorization)
+	assert.Equal(t, "HMAC-SHA256", verified.Get("oauth_signature_method"))
+	assert.Equal(t, "token_id", verified.Get("oauth_token"))
+}
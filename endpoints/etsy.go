@@ -0,0 +1,23 @@
+package endpoints
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// Etsy is the Endpoint of the Etsy API v2. Etsy grants the scopes requested
+// with the temporary credentials, passed with EtsyScopes.
+var Etsy = oauth1.Endpoint{
+	RequestTokenURL: "https://openapi.etsy.com/v2/oauth/request_token",
+	AuthorizeURL:    "https://www.etsy.com/oauth/signin",
+	AccessTokenURL:  "https://openapi.etsy.com/v2/oauth/access_token",
+}
+
+// EtsyScopes returns the space-delimited scope parameter requesting
+// scopes, such as "email_r" and "listings_w", to be passed to
+// Config.RequestTokenWithParams.
+func EtsyScopes(scopes ...string) url.Values {
+	return url.Values{"scope": {strings.Join(scopes, " ")}}
+}
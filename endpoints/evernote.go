@@ -0,0 +1,27 @@
+package endpoints
+
+import (
+	"github.com/ktnyt/oauth1"
+)
+
+// Evernote returns the Endpoint of the Evernote production service, or of
+// its sandbox for development if sandbox is true.
+//
+// Evernote omits oauth_callback_confirmed from request token responses and
+// issues empty token secrets, so the Endpoint is CallbackUnconfirmed and
+// has an EmptyTokenSecret. Its token responses carry the edam_* parameters
+// needed to use the API, such as edam_noteStoreUrl, returned by
+// Config.AccessTokenResponse.
+func Evernote(sandbox bool) oauth1.Endpoint {
+	host := "https://www.evernote.com"
+	if sandbox {
+		host = "https://sandbox.evernote.com"
+	}
+	return oauth1.Endpoint{
+		RequestTokenURL:     host + "/oauth",
+		AuthorizeURL:        host + "/OAuth.action",
+		AccessTokenURL:      host + "/oauth",
+		CallbackUnconfirmed: true,
+		EmptyTokenSecret:    true,
+	}
+}
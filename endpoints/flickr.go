@@ -0,0 +1,32 @@
+package endpoints
+
+import (
+	"net/url"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// Flickr is the Endpoint of the Flickr API. Users are asked for the
+// permission given with FlickrPerms.AuthorizeParams.
+var Flickr = oauth1.Endpoint{
+	RequestTokenURL: "https://www.flickr.com/services/oauth/request_token",
+	AuthorizeURL:    "https://www.flickr.com/services/oauth/authorize",
+	AccessTokenURL:  "https://www.flickr.com/services/oauth/access_token",
+}
+
+// FlickrPerms is the permission Flickr users grant an app. Each permission
+// includes the ones before it.
+type FlickrPerms string
+
+// Flickr permissions.
+const (
+	FlickrRead   FlickrPerms = "read"
+	FlickrWrite  FlickrPerms = "write"
+	FlickrDelete FlickrPerms = "delete"
+)
+
+// AuthorizeParams returns the perms parameter asking for p, to be passed to
+// Config.AuthorizationURLWithParams.
+func (p FlickrPerms) AuthorizeParams() url.Values {
+	return url.Values{"perms": {string(p)}}
+}
@@ -0,0 +1,13 @@
+package endpoints
+
+import (
+	"github.com/ktnyt/oauth1"
+)
+
+// Garmin is the Endpoint of the Garmin Connect developer APIs, such as the
+// Health and Activity APIs.
+var Garmin = oauth1.Endpoint{
+	RequestTokenURL: "https://connectapi.garmin.com/oauth-service/oauth/request_token",
+	AuthorizeURL:    "https://connect.garmin.com/oauthConfirm",
+	AccessTokenURL:  "https://connectapi.garmin.com/oauth-service/oauth/access_token",
+}
@@ -0,0 +1,22 @@
+package endpoints
+
+import (
+	"strings"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// NetSuite returns a Config for the Token-Based Authentication of the
+// NetSuite account accountID, such as "1234567" or "1234567_SB1" for a
+// sandbox: requests are signed with HMAC-SHA256 and carry the account ID as
+// their realm. NetSuite issues token credentials out of band, from its
+// Access Tokens page, so there is no authorization flow; make clients with
+// config.Client(ctx, tokenID, tokenSecret).
+func NetSuite(accountID, consumerKey, consumerSecret string) *oauth1.Config {
+	return &oauth1.Config{
+		ConsumerKey:    consumerKey,
+		ConsumerSecret: consumerSecret,
+		Realm:          strings.ToUpper(accountID),
+		Endpoint:       oauth1.Endpoint{SignatureMethod: "HMAC-SHA256"},
+	}
+}
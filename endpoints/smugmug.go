@@ -0,0 +1,45 @@
+package endpoints
+
+import (
+	"net/url"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// SmugMug is the Endpoint of the SmugMug API v2. Users are asked for the
+// access given with SmugMugAuthorizeParams.
+var SmugMug = oauth1.Endpoint{
+	RequestTokenURL: "https://api.smugmug.com/services/oauth/1.0a/getRequestToken",
+	AuthorizeURL:    "https://api.smugmug.com/services/oauth/1.0a/authorize",
+	AccessTokenURL:  "https://api.smugmug.com/services/oauth/1.0a/getAccessToken",
+}
+
+// SmugMugAccess is which content of SmugMug users an app may access.
+type SmugMugAccess string
+
+// SmugMug access levels.
+const (
+	SmugMugPublic SmugMugAccess = "Public"
+	SmugMugFull   SmugMugAccess = "Full"
+)
+
+// SmugMugPermissions is what an app may do with the content it accesses.
+// Each permission includes the ones before it.
+type SmugMugPermissions string
+
+// SmugMug permissions.
+const (
+	SmugMugRead   SmugMugPermissions = "Read"
+	SmugMugAdd    SmugMugPermissions = "Add"
+	SmugMugModify SmugMugPermissions = "Modify"
+)
+
+// SmugMugAuthorizeParams returns the Access and Permissions parameters
+// asking for access and permissions, to be passed to
+// Config.AuthorizationURLWithParams.
+func SmugMugAuthorizeParams(access SmugMugAccess, permissions SmugMugPermissions) url.Values {
+	return url.Values{
+		"Access":      {string(access)},
+		"Permissions": {string(permissions)},
+	}
+}
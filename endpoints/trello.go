@@ -0,0 +1,52 @@
+package endpoints
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// Trello is the Endpoint of the Trello API. Its authorization page only
+// grants read access for 30 days unless asked otherwise with
+// TrelloAuthorizeParams.
+var Trello = oauth1.Endpoint{
+	RequestTokenURL: "https://trello.com/1/OAuthGetRequestToken",
+	AuthorizeURL:    "https://trello.com/1/OAuthAuthorizeToken",
+	AccessTokenURL:  "https://trello.com/1/OAuthGetAccessToken",
+}
+
+// TrelloOption sets a parameter of Trello's authorization page.
+type TrelloOption func(url.Values)
+
+// TrelloScope asks for scopes, such as "read", "write" and "account".
+func TrelloScope(scopes ...string) TrelloOption {
+	return func(params url.Values) {
+		params.Set("scope", strings.Join(scopes, ","))
+	}
+}
+
+// TrelloExpiration asks for tokens expiring after expiration: "1hour",
+// "1day", "30days" or "never".
+func TrelloExpiration(expiration string) TrelloOption {
+	return func(params url.Values) {
+		params.Set("expiration", expiration)
+	}
+}
+
+// TrelloName sets the app name shown to users.
+func TrelloName(name string) TrelloOption {
+	return func(params url.Values) {
+		params.Set("name", name)
+	}
+}
+
+// TrelloAuthorizeParams returns the parameters set by opts, to be passed
+// to Config.AuthorizationURLWithParams.
+func TrelloAuthorizeParams(opts ...TrelloOption) url.Values {
+	params := make(url.Values)
+	for _, opt := range opts {
+		opt(params)
+	}
+	return params
+}
@@ -0,0 +1,18 @@
+package endpoints
+
+import (
+	"github.com/ktnyt/oauth1"
+)
+
+// Tumblr is the Endpoint of the Tumblr API.
+//
+// Tumblr does not support out-of-band authorization: the temporary
+// credential request must carry a callback URL, even for apps which cannot
+// receive the redirect, as "oob" is refused. Set CallbackURL to any URL
+// registered for the app and, if need be, have users paste the
+// oauth_verifier from the address they are redirected to.
+var Tumblr = oauth1.Endpoint{
+	RequestTokenURL: "https://www.tumblr.com/oauth/request_token",
+	AuthorizeURL:    "https://www.tumblr.com/oauth/authorize",
+	AccessTokenURL:  "https://www.tumblr.com/oauth/access_token",
+}
@@ -0,0 +1,33 @@
+package endpoints
+
+import (
+	"github.com/ktnyt/oauth1"
+)
+
+// Twitter is the Endpoint of Twitter (X). Its AuthorizeURL is
+// oauth/authorize, which asks users to grant access every time, and its
+// AuthenticateURL is oauth/authenticate, which redirects users who granted
+// access before straight to the callback; pick one with
+// Config.AuthorizationURL or Config.AuthenticationURL.
+var Twitter = oauth1.Endpoint{
+	RequestTokenURL: "https://api.twitter.com/oauth/request_token",
+	AuthorizeURL:    "https://api.twitter.com/oauth/authorize",
+	AuthenticateURL: "https://api.twitter.com/oauth/authenticate",
+	AccessTokenURL:  "https://api.twitter.com/oauth/access_token",
+}
+
+// TwitterDenied is the callback query parameter Twitter sends, carrying the
+// request token, in place of oauth_token and oauth_verifier when the user
+// cancels authorization.
+const TwitterDenied = "denied"
+
+// Error codes of Twitter's request token endpoint for callbacks it refuses.
+const (
+	// TwitterCallbackNotApproved reports an oauth_callback which is not
+	// among the callback URLs registered for the app
+	TwitterCallbackNotApproved = 415
+
+	// TwitterCallbackOOBOnly reports an oauth_callback other than "oob" for
+	// desktop apps
+	TwitterCallbackOOBOnly = 417
+)
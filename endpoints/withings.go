@@ -0,0 +1,16 @@
+package endpoints
+
+import (
+	"github.com/ktnyt/oauth1"
+)
+
+// Withings is the Endpoint of the legacy OAuth1 Withings API, which expects
+// the protocol parameters of signed requests in the query string, so the
+// Endpoint is ParamsInQuery. Token responses carry the userid required by
+// API calls, returned by Config.AccessTokenResponse.
+var Withings = oauth1.Endpoint{
+	RequestTokenURL: "https://oauth.withings.com/account/request_token",
+	AuthorizeURL:    "https://oauth.withings.com/account/authorize",
+	AccessTokenURL:  "https://oauth.withings.com/account/access_token",
+	ParamsInQuery:   true,
+}
@@ -0,0 +1,46 @@
+package endpoints
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// Xero is the Endpoint of the Xero accounting API for public apps, which
+// sign with HMAC-SHA1. Private and Partner apps sign with RSA-SHA1 instead,
+// see XeroPrivateClient and XeroPartner.
+var Xero = oauth1.Endpoint{
+	RequestTokenURL: "https://api.xero.com/oauth/RequestToken",
+	AuthorizeURL:    "https://api.xero.com/oauth/Authorize",
+	AccessTokenURL:  "https://api.xero.com/oauth/AccessToken",
+}
+
+// XeroPrivateClient returns an HTTP client for the Xero private app
+// consumerKey, which is connected to a single organisation without any
+// authorization flow: requests are signed two-legged with RSA-SHA1 by the
+// private key of the app's certificate, with the consumer key as the
+// oauth_token.
+func XeroPrivateClient(ctx context.Context, consumerKey string, key *rsa.PrivateKey) *http.Client {
+	config := &oauth1.Config{
+		ConsumerKey: consumerKey,
+		Endpoint:    Xero,
+		Signer:      oauth1.RSASigner{PrivateKey: key},
+	}
+	return config.Client(ctx, consumerKey, "")
+}
+
+// XeroPartner returns a Config for the Xero partner app consumerKey, which
+// signs with RSA-SHA1 by the private key of the app's certificate. Partner
+// access tokens expire after 30 minutes and carry a session handle to
+// refresh them with, so make clients with
+// config.TokenSourceClient(ctx, config.RefreshingTokenSource(token)).
+func XeroPartner(consumerKey string, key *rsa.PrivateKey, callbackURL string) *oauth1.Config {
+	return &oauth1.Config{
+		ConsumerKey: consumerKey,
+		CallbackURL: callbackURL,
+		Endpoint:    Xero,
+		Signer:      oauth1.RSASigner{PrivateKey: key},
+	}
+}
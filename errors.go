@@ -0,0 +1,43 @@
+package oauth1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrMissingToken is returned when a token endpoint response lacks the
+	// oauth_token or oauth_token_secret parameter.
+	ErrMissingToken = errors.New("oauth1: Response missing oauth_token or oauth_token_secret")
+
+	// ErrCallbackNotConfirmed is returned when a request token response
+	// does not confirm the callback with oauth_callback_confirmed=true.
+	ErrCallbackNotConfirmed = errors.New("oauth1: oauth_callback_confirmed was not true")
+
+	// ErrMissingVerifier is returned when an authorization callback lacks
+	// the oauth_token or oauth_verifier parameter.
+	ErrMissingVerifier = errors.New("oauth1: Request missing oauth_token or oauth_verifier")
+)
+
+// TokenRequestError is returned when a token endpoint responds with a status
+// other than 200 OK or 201 Created.
+type TokenRequestError struct {
+	// Endpoint URL the request was sent to
+	Endpoint string
+
+	// StatusCode, Header and Body of the response
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Problem reported by the provider, if any
+	Problem *Problem
+}
+
+func (e *TokenRequestError) Error() string {
+	if e.Problem != nil {
+		return fmt.Sprintf("oauth1: Server returned unexpected status %d: %s", e.StatusCode, e.Problem.Problem)
+	}
+	return fmt.Sprintf("oauth1: Server returned unexpected status %d", e.StatusCode)
+}
@@ -0,0 +1,67 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRequestError(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Invalid consumer key"))
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{RequestTokenURL: server.URL + "/oauth/request_token"}}
+	_, _, err := config.RequestToken()
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Server returned unexpected status 401", err.Error())
+	}
+	var tokenErr *TokenRequestError
+	if assert.True(t, errors.As(err, &tokenErr)) {
+		assert.Equal(t, http.StatusUnauthorized, tokenErr.StatusCode)
+		assert.Equal(t, "Invalid consumer key", string(tokenErr.Body))
+		assert.Equal(t, server.URL+"/oauth/request_token", tokenErr.Endpoint)
+	}
+}
+
+func TestErrMissingToken(t *testing.T) {
+	server := newAccessTokenServer(t, url.Values{"oauth_token": {"access_token"}})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{AccessTokenURL: server.URL}}
+	_, _, err := config.AccessToken("request_token", "request_secret", expectedVerifier)
+	assert.True(t, errors.Is(err, ErrMissingToken))
+}
+
+func TestEndpointEmptyTokenSecret(t *testing.T) {
+	server := newAccessTokenServer(t, url.Values{"oauth_token": {"access_token"}, "oauth_token_secret": {""}})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{AccessTokenURL: server.URL, EmptyTokenSecret: true}}
+	accessToken, accessSecret, err := config.AccessToken("request_token", "request_secret", expectedVerifier)
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", accessToken)
+	assert.Equal(t, "", accessSecret)
+}
+
+func TestErrCallbackNotConfirmed(t *testing.T) {
+	server := newRequestTokenServer(t, url.Values{
+		"oauth_token":        {"request_token"},
+		"oauth_token_secret": {"request_secret"},
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{RequestTokenURL: server.URL}}
+	_, _, err := config.RequestToken()
+	assert.True(t, errors.Is(err, ErrCallbackNotConfirmed))
+
+	config.Endpoint.CallbackUnconfirmed = true
+	requestToken, _, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+}
@@ -0,0 +1,7 @@
+module github.com/ktnyt/oauth1/examples
+
+go 1.14
+
+require github.com/ktnyt/oauth1 v0.0.0
+
+replace github.com/ktnyt/oauth1 => ../
@@ -0,0 +1,149 @@
+package oauth1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenFormatVersion is the version of the portable token format written by
+// ExportToken.
+const TokenFormatVersion = 1
+
+// TokenMetadata describes an exported Token.
+type TokenMetadata struct {
+	// Provider name, e.g. "twitter"
+	Provider string
+
+	// ConsumerKey the token was issued to. Only its fingerprint is exported.
+	ConsumerKey string
+
+	// Expiry of the token, zero if it does not expire
+	Expiry time.Time
+
+	// SessionHandle for refreshing the token, if the provider issued one
+	SessionHandle string
+}
+
+// exportedToken is the JSON document of the portable token format.
+type exportedToken struct {
+	Version                int        `json:"version"`
+	Provider               string     `json:"provider,omitempty"`
+	ConsumerKeyFingerprint string     `json:"consumer_key_fingerprint,omitempty"`
+	Token                  string     `json:"oauth_token"`
+	TokenSecret            string     `json:"oauth_token_secret"`
+	Expiry                 *time.Time `json:"expiry,omitempty"`
+	SessionHandle          string     `json:"oauth_session_handle,omitempty"`
+}
+
+// ConsumerKeyFingerprint returns a short fingerprint identifying a consumer
+// key without revealing it.
+func ConsumerKeyFingerprint(consumerKey string) string {
+	sum := sha256.Sum256([]byte(consumerKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ExportToken encodes token and its metadata in the versioned, portable JSON
+// format read by ImportToken.
+//
+// The Expiry and SessionHandle of meta default to those of token.
+func ExportToken(token *Token, meta TokenMetadata) ([]byte, error) {
+	if meta.Expiry.IsZero() {
+		meta.Expiry = token.Expiry
+	}
+	if meta.SessionHandle == "" {
+		meta.SessionHandle = token.SessionHandle
+	}
+	exported := exportedToken{
+		Version:       TokenFormatVersion,
+		Provider:      meta.Provider,
+		Token:         token.Token,
+		TokenSecret:   token.TokenSecret,
+		SessionHandle: meta.SessionHandle,
+	}
+	if meta.ConsumerKey != "" {
+		exported.ConsumerKeyFingerprint = ConsumerKeyFingerprint(meta.ConsumerKey)
+	}
+	if !meta.Expiry.IsZero() {
+		expiry := meta.Expiry.UTC()
+		exported.Expiry = &expiry
+	}
+	return json.MarshalIndent(exported, "", "  ")
+}
+
+// ImportToken decodes a Token exported by ExportToken. If consumerKey is not
+// empty, the token must have been exported for that consumer key. The
+// returned metadata holds consumerKey rather than the exported fingerprint.
+func ImportToken(data []byte, consumerKey string) (*Token, *TokenMetadata, error) {
+	var exported exportedToken
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, nil, err
+	}
+	if exported.Version != TokenFormatVersion {
+		return nil, nil, fmt.Errorf("oauth1: Unsupported token format version %d", exported.Version)
+	}
+	if exported.Token == "" {
+		return nil, nil, errors.New("oauth1: Exported token missing oauth_token")
+	}
+	if consumerKey != "" && exported.ConsumerKeyFingerprint != ConsumerKeyFingerprint(consumerKey) {
+		return nil, nil, errors.New("oauth1: Exported token belongs to a different consumer key")
+	}
+	meta := &TokenMetadata{
+		Provider:      exported.Provider,
+		ConsumerKey:   consumerKey,
+		SessionHandle: exported.SessionHandle,
+	}
+	if exported.Expiry != nil {
+		meta.Expiry = *exported.Expiry
+	}
+	token := &Token{
+		Token:         exported.Token,
+		TokenSecret:   exported.TokenSecret,
+		Expiry:        meta.Expiry,
+		SessionHandle: meta.SessionHandle,
+	}
+	return token, meta, nil
+}
+
+// FileTokenStore is a TokenStore keeping each Token in a file of Dir using
+// the portable token format.
+type FileTokenStore struct {
+	// Dir holding the token files
+	Dir string
+
+	// Metadata written along with each Token
+	Metadata TokenMetadata
+}
+
+func (s *FileTokenStore) path(key string) string {
+	return filepath.Join(s.Dir, url.PathEscape(key)+".json")
+}
+
+// Load returns the Token stored for key or ErrNoToken.
+func (s *FileTokenStore) Load(key string) (*Token, error) {
+	data, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	token, _, err := ImportToken(data, s.Metadata.ConsumerKey)
+	return token, err
+}
+
+// Save writes token to the file for key, readable by the owner only.
+func (s *FileTokenStore) Save(key string, token *Token) error {
+	data, err := ExportToken(token, s.Metadata)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path(key), data, 0600)
+}
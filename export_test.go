@@ -0,0 +1,89 @@
+package oauth1
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportToken(t *testing.T) {
+	token := &Token{Token: "token", TokenSecret: "secret"}
+	meta := TokenMetadata{
+		Provider:      "yahoo",
+		ConsumerKey:   "consumer_key",
+		Expiry:        time.Unix(1318467427, 0),
+		SessionHandle: "session_handle",
+	}
+	data, err := ExportToken(token, meta)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "consumer_key\"")
+	assert.Contains(t, string(data), ConsumerKeyFingerprint("consumer_key"))
+
+	imported, importedMeta, err := ImportToken(data, "consumer_key")
+	assert.Nil(t, err)
+	assert.Equal(t, "token", imported.Token)
+	assert.Equal(t, "secret", imported.TokenSecret)
+	assert.True(t, meta.Expiry.Equal(imported.Expiry))
+	assert.Equal(t, "session_handle", imported.SessionHandle)
+	assert.Equal(t, "yahoo", importedMeta.Provider)
+	assert.Equal(t, "consumer_key", importedMeta.ConsumerKey)
+	assert.True(t, meta.Expiry.Equal(importedMeta.Expiry))
+	assert.Equal(t, "session_handle", importedMeta.SessionHandle)
+
+	// tokens can be imported without checking the consumer key
+	_, _, err = ImportToken(data, "")
+	assert.Nil(t, err)
+}
+
+func TestExportToken_TokenExpiryAndSessionHandle(t *testing.T) {
+	token := &Token{Token: "token", TokenSecret: "secret", Expiry: time.Unix(1318467427, 0), SessionHandle: "session_handle"}
+	data, err := ExportToken(token, TokenMetadata{Provider: "yahoo"})
+	assert.Nil(t, err)
+
+	imported, meta, err := ImportToken(data, "")
+	assert.Nil(t, err)
+	assert.True(t, token.Expiry.Equal(imported.Expiry))
+	assert.True(t, token.Expiry.Equal(meta.Expiry))
+	assert.Equal(t, "session_handle", imported.SessionHandle)
+}
+
+func TestImportToken_Errors(t *testing.T) {
+	data, err := ExportToken(&Token{Token: "token"}, TokenMetadata{ConsumerKey: "consumer_key"})
+	assert.Nil(t, err)
+	_, _, err = ImportToken(data, "other_consumer_key")
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Exported token belongs to a different consumer key", err.Error())
+	}
+
+	_, _, err = ImportToken([]byte(`{"version":2,"oauth_token":"token"}`), "")
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Unsupported token format version 2", err.Error())
+	}
+	_, _, err = ImportToken([]byte(`{"version":1}`), "")
+	assert.Error(t, err)
+	_, _, err = ImportToken([]byte(`not json`), "")
+	assert.Error(t, err)
+}
+
+func TestFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oauth1")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store := &FileTokenStore{Dir: dir, Metadata: TokenMetadata{Provider: "twitter", ConsumerKey: "consumer_key"}}
+	_, err = store.Load("user/1")
+	assert.Equal(t, ErrNoToken, err)
+
+	token := &Token{Token: "token", TokenSecret: "secret"}
+	assert.Nil(t, store.Save("user/1", token))
+	loaded, err := store.Load("user/1")
+	assert.Nil(t, err)
+	assert.Equal(t, token, loaded)
+
+	other := &FileTokenStore{Dir: dir, Metadata: TokenMetadata{ConsumerKey: "other_key"}}
+	_, err = other.Load("user/1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package oauth1
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+func requestTokenURL(e Endpoint) string { return e.RequestTokenURL }
+func accessTokenURL(e Endpoint) string  { return e.AccessTokenURL }
+
+// withQuery returns an endpoint URL func adding params to the query of the
+// URLs selected by endpointURL.
+func withQuery(endpointURL func(Endpoint) string, params url.Values) func(Endpoint) string {
+	if len(params) == 0 {
+		return endpointURL
+	}
+	return func(e Endpoint) string {
+		u, err := url.Parse(endpointURL(e))
+		if err != nil {
+			// left for http.NewRequest to report
+			return endpointURL(e)
+		}
+		query := u.Query()
+		for key, values := range params {
+			for i := range values {
+				query.Add(key, values[i])
+			}
+		}
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+}
+
+// endpoints returns the Endpoint followed by its Fallbacks.
+func (e Endpoint) endpoints() []Endpoint {
+	return append([]Endpoint{e}, e.Fallbacks...)
+}
+
+// activeEndpoint returns the endpoint which last served a token request
+// successfully, or the primary Endpoint if none has yet.
+func (c *Config) activeEndpoint() Endpoint {
+	endpoints := c.Endpoint.endpoints()
+	i := int(atomic.LoadInt32(&c.active))
+	if i < 0 || i >= len(endpoints) {
+		return c.Endpoint
+	}
+	return endpoints[i]
+}
+
+// failover builds a request for each endpoint in turn, starting from the
+// active one, and sends it with do. Only connection errors reported by do
+// move on to the next endpoint, unless the context of the request is done;
+// the endpoint which succeeds becomes the active one for subsequent calls.
+func (c *Config) failover(build func(Endpoint) (*http.Request, error), do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	endpoints := c.Endpoint.endpoints()
+	start := int(atomic.LoadInt32(&c.active))
+	if start < 0 || start >= len(endpoints) {
+		start = 0
+	}
+	var lastErr error
+	for i := range endpoints {
+		n := (start + i) % len(endpoints)
+		req, err := build(endpoints[n])
+		if err != nil {
+			return nil, err
+		}
+		res, err := do(req)
+		if err != nil {
+			if req.Context().Err() != nil || !isConnectionError(err) {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		atomic.StoreInt32(&c.active, int32(n))
+		return res, nil
+	}
+	return nil, lastErr
+}
+
+// isConnectionError reports whether err failed to reach the endpoint, such
+// as a refused connection or an unresolvable host.
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// preferHealthy makes the first healthy endpoint active unless the active
+// endpoint is healthy. healthy is indexed like Endpoint.endpoints().
+func (c *Config) preferHealthy(healthy []bool) {
+	active := int(atomic.LoadInt32(&c.active))
+	if active >= 0 && active < len(healthy) && healthy[active] {
+		return
+	}
+	for i := range healthy {
+		if healthy[i] {
+			atomic.StoreInt32(&c.active, int32(i))
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newClosedServerURL returns the URL of a server which refuses connections.
+func newClosedServerURL() string {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {})
+	server.Close()
+	return server.URL
+}
+
+func TestConfigRequestToken_Failover(t *testing.T) {
+	data := url.Values{}
+	data.Add("oauth_token", "request_token")
+	data.Add("oauth_token_secret", "request_secret")
+	data.Add("oauth_callback_confirmed", "true")
+	server := newRequestTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: newClosedServerURL(),
+			AuthorizeURL:    "https://primary.example.com/authorize",
+			Fallbacks: []Endpoint{
+				{
+					RequestTokenURL: server.URL,
+					AuthorizeURL:    "https://mirror.example.com/authorize",
+				},
+			},
+		},
+	}
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+	assert.Equal(t, "request_secret", requestSecret)
+
+	// the healthy mirror is used for the rest of the flow
+	authorizationURL, err := config.AuthorizationURL(requestToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "mirror.example.com", authorizationURL.Host)
+}
+
+func TestConfigFailover_Sticky(t *testing.T) {
+	hits := map[string]int{}
+	do := func(req *http.Request) (*http.Response, error) {
+		hits[req.URL.Host]++
+		if req.URL.Host == "down.example.com" {
+			return nil, &url.Error{Op: "Post", URL: req.URL.String(), Err: &net.OpError{Op: "dial", Net: "tcp", Err: assert.AnError}}
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+	build := func(e Endpoint) (*http.Request, error) {
+		return http.NewRequest("POST", e.RequestTokenURL, nil)
+	}
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: "https://down.example.com/request_token",
+			Fallbacks: []Endpoint{
+				{RequestTokenURL: "https://up.example.com/request_token"},
+			},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		_, err := config.failover(build, do)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 1, hits["down.example.com"])
+	assert.Equal(t, 3, hits["up.example.com"])
+}
+
+func TestConfigFailover_AllEndpointsDown(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: newClosedServerURL(),
+			Fallbacks: []Endpoint{
+				{RequestTokenURL: newClosedServerURL()},
+			},
+		},
+	}
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.NotNil(t, err)
+	assert.Equal(t, "", requestToken)
+	assert.Equal(t, "", requestSecret)
+}
+
+func TestConfigFailover_NoFailoverOnErrorStatus(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer server.Close()
+	fallbackHit := false
+	fallback := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		fallbackHit = true
+	})
+	defer fallback.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			AccessTokenURL: server.URL,
+			Fallbacks: []Endpoint{
+				{AccessTokenURL: fallback.URL},
+			},
+		},
+	}
+	_, _, err := config.AccessToken("request_token", "request_secret", expectedVerifier)
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Server returned unexpected status 500", err.Error())
+	}
+	assert.False(t, fallbackHit)
+}
+
+func TestConfigFailover_NoFailoverOnOtherErrors(t *testing.T) {
+	hits := map[string]int{}
+	build := func(e Endpoint) (*http.Request, error) {
+		return http.NewRequest("POST", e.RequestTokenURL, nil)
+	}
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: "https://primary.example.com/request_token",
+			Fallbacks: []Endpoint{
+				{RequestTokenURL: "https://mirror.example.com/request_token"},
+			},
+		},
+	}
+	_, err := config.failover(build, func(req *http.Request) (*http.Response, error) {
+		hits[req.URL.Host]++
+		return nil, &url.Error{Op: "Post", URL: req.URL.String(), Err: assert.AnError}
+	})
+	assert.True(t, errors.Is(err, assert.AnError))
+	assert.Equal(t, 0, hits["mirror.example.com"])
+
+	// a cancelled request does not walk the fallbacks, even on dial errors
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = config.failover(func(e Endpoint) (*http.Request, error) {
+		req, err := build(e)
+		if err != nil {
+			return nil, err
+		}
+		return req.WithContext(ctx), nil
+	}, func(req *http.Request) (*http.Response, error) {
+		hits[req.URL.Host]++
+		return nil, &url.Error{Op: "Post", URL: req.URL.String(), Err: &net.OpError{Op: "dial", Net: "tcp", Err: context.Canceled}}
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 2, hits["primary.example.com"])
+	assert.Equal(t, 0, hits["mirror.example.com"])
+}
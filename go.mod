@@ -0,0 +1,8 @@
+module github.com/ktnyt/oauth1
+
+go 1.14
+
+require (
+	github.com/stretchr/testify v1.6.1
+	google.golang.org/appengine v1.6.8
+)
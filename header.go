@@ -0,0 +1,116 @@
+package oauth1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseOAuthHeader parses the value of an OAuth Authorization or
+// WWW-Authenticate header, such as `OAuth realm="Example",
+// oauth_consumer_key="key", ...`, into its parameters, percent decoded. The
+// scheme is matched case-insensitively, whitespace around parameters and
+// separators is optional, values may be unquoted and parameters without a
+// value have an empty one. The realm is returned as the "realm" parameter,
+// unquoted but not percent decoded. See RFC 5849 3.5.1.
+func ParseOAuthHeader(header string) (url.Values, error) {
+	header = strings.TrimSpace(header)
+	const scheme = "OAuth"
+	if len(header) < len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) ||
+		len(header) > len(scheme) && !isSpace(header[len(scheme)]) {
+		return nil, errors.New("oauth1: Header does not use the OAuth scheme")
+	}
+	params := make(url.Values)
+	rest := header[len(scheme):]
+	for {
+		rest = strings.TrimLeft(rest, whitespace+",")
+		if rest == "" {
+			return params, nil
+		}
+		end := strings.IndexAny(rest, whitespace+"=,")
+		if end < 0 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		if name == "" {
+			return nil, errors.New("oauth1: OAuth header parameter has no name")
+		}
+		rest = strings.TrimLeft(rest[end:], whitespace)
+		if !strings.HasPrefix(rest, "=") {
+			params.Add(name, "")
+			continue
+		}
+		rest = strings.TrimLeft(rest[1:], whitespace)
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var err error
+			if value, rest, err = unquote(rest); err != nil {
+				return nil, fmt.Errorf("oauth1: OAuth header parameter %s: %v", name, err)
+			}
+		} else {
+			end := strings.IndexAny(rest, whitespace+",")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		if name != "realm" {
+			decoded, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, fmt.Errorf("oauth1: OAuth header parameter %s: %v", name, err)
+			}
+			value = decoded
+		}
+		params.Add(name, value)
+		rest = strings.TrimLeft(rest, whitespace)
+		if rest != "" && rest[0] != ',' {
+			return nil, fmt.Errorf("oauth1: OAuth header parameter %s is not followed by a comma", name)
+		}
+	}
+}
+
+// unquote returns the content of the quoted-string s starts with, with
+// backslash escapes removed, and the remainder of s.
+func unquote(s string) (string, string, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), s[i+1:], nil
+		case '\\':
+			i++
+			if i == len(s) {
+				break
+			}
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", errors.New("unterminated quoted value")
+}
+
+// whitespace separating OAuth header parameters, line breaks included as
+// headers may be folded
+const whitespace = " \t\r\n"
+
+func isSpace(c byte) bool {
+	return strings.IndexByte(whitespace, c) >= 0
+}
+
+// FormatOAuthHeader formats params as the value of an OAuth Authorization
+// header, preceded by realm if non-empty. Names and values are percent
+// encoded and sorted by name and then value. See RFC 5849 3.5.1.
+func FormatOAuthHeader(realm string, params url.Values) string {
+	pairs := encodePairs(params)
+	formatted := make([]string, 0, len(pairs)+1)
+	if realm != "" {
+		quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(realm)
+		formatted = append(formatted, fmt.Sprintf("realm=\"%s\"", quoted))
+	}
+	for _, p := range pairs {
+		formatted = append(formatted, fmt.Sprintf("%s=\"%s\"", p.name, p.value))
+	}
+	return fmt.Sprintf("OAuth %s", strings.Join(formatted, ", "))
+}
@@ -0,0 +1,90 @@
+package oauth1
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOAuthHeader(t *testing.T) {
+	// RFC 5849 3.5.1 example
+	header := `OAuth realm="Example",
+        oauth_consumer_key="0685bd9184jfhq22",
+        oauth_token="ad180jjd733klru7",
+        oauth_signature_method="HMAC-SHA1",
+        oauth_signature="wOJIO9A2W5mFwDgiDvZbTSMK%2FPY%3D",
+        oauth_timestamp="137131200",
+        oauth_nonce="4572616e48616d6d65724c61686176",
+        oauth_version="1.0"`
+	params, err := ParseOAuthHeader(header)
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{
+		"realm":                  {"Example"},
+		"oauth_consumer_key":     {"0685bd9184jfhq22"},
+		"oauth_token":            {"ad180jjd733klru7"},
+		"oauth_signature_method": {"HMAC-SHA1"},
+		"oauth_signature":        {"wOJIO9A2W5mFwDgiDvZbTSMK/PY="},
+		"oauth_timestamp":        {"137131200"},
+		"oauth_nonce":            {"4572616e48616d6d65724c61686176"},
+		"oauth_version":          {"1.0"},
+	}, params)
+}
+
+func TestParseOAuthHeader_Tolerant(t *testing.T) {
+	cases := []struct {
+		header   string
+		expected url.Values
+	}{
+		{`oauth oauth_token="token"`, url.Values{"oauth_token": {"token"}}},
+		{`OAuth oauth_token="token",oauth_nonce="nonce"`, url.Values{"oauth_token": {"token"}, "oauth_nonce": {"nonce"}}},
+		{"OAuth\toauth_token = \"token\" ,\t oauth_nonce=nonce ,,", url.Values{"oauth_token": {"token"}, "oauth_nonce": {"nonce"}}},
+		{`OAuth oauth_callback="", oauth_flag`, url.Values{"oauth_callback": {""}, "oauth_flag": {""}}},
+		{`OAuth realm="Example, \"Inc\"", oauth_note="a+b%20c%3D="`, url.Values{"realm": {`Example, "Inc"`}, "oauth_note": {"a+b c=="}}},
+		{`OAuth`, url.Values{}},
+	}
+	for _, c := range cases {
+		params, err := ParseOAuthHeader(c.header)
+		assert.Nil(t, err, c.header)
+		assert.Equal(t, c.expected, params, c.header)
+	}
+}
+
+func TestParseOAuthHeader_Errors(t *testing.T) {
+	cases := []struct {
+		header, err string
+	}{
+		{`Basic dXNlcjpwYXNz`, "oauth1: Header does not use the OAuth scheme"},
+		{`OAuthoauth_token="token"`, "oauth1: Header does not use the OAuth scheme"},
+		{`OAuth ="token"`, "oauth1: OAuth header parameter has no name"},
+		{`OAuth oauth_token="token`, "oauth1: OAuth header parameter oauth_token: unterminated quoted value"},
+		{`OAuth oauth_token="%zz"`, `oauth1: OAuth header parameter oauth_token: invalid URL escape "%zz"`},
+		{`OAuth oauth_token="token" oauth_nonce="nonce"`, "oauth1: OAuth header parameter oauth_token is not followed by a comma"},
+	}
+	for _, c := range cases {
+		_, err := ParseOAuthHeader(c.header)
+		assert.EqualError(t, err, c.err, c.header)
+	}
+}
+
+func TestFormatOAuthHeader_Realm(t *testing.T) {
+	params := url.Values{"oauth_token": {"a b"}}
+	assert.Equal(t, `OAuth realm="Example \"Inc\" \\ Co", oauth_token="a%20b"`, FormatOAuthHeader(`Example "Inc" \ Co`, params))
+	assert.Equal(t, `OAuth realm="Example"`, FormatOAuthHeader("Example", nil))
+}
+
+func TestFormatOAuthHeader_RoundTrip(t *testing.T) {
+	params := url.Values{
+		"realm":           {`Example, "Inc"`},
+		"oauth_signature": {"a+b/c="},
+		"oauth_callback":  {"https://example.com/コールバック?a=1&b=2"},
+		"oauth_token":     {""},
+	}
+	parsed, err := ParseOAuthHeader(FormatOAuthHeader(params.Get("realm"), url.Values{
+		"oauth_signature": params["oauth_signature"],
+		"oauth_callback":  params["oauth_callback"],
+		"oauth_token":     params["oauth_token"],
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, params, parsed)
+}
@@ -0,0 +1,166 @@
+package oauth1
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProbeInterval = 30 * time.Second
+	defaultProbeTimeout  = 5 * time.Second
+)
+
+// EndpointStatus is the outcome of the most recent probe of a URL.
+type EndpointStatus struct {
+	URL     string
+	Healthy bool
+	Checked time.Time
+}
+
+// HealthProber periodically probes the token endpoints of a Config (the
+// Endpoint and its Fallbacks) and optionally the resource API base URL.
+// Token requests fail over away from endpoints found unreachable, and every
+// result is reported to the Config's Metrics.
+//
+// An endpoint is healthy when it answers with any HTTP response; only
+// connection errors and timeouts mark it unhealthy.
+type HealthProber struct {
+	// Config whose endpoints are probed
+	Config *Config
+
+	// APIBaseURL is the resource API base URL, probed when non-empty
+	APIBaseURL string
+
+	// Interval between probes, 30 seconds if zero
+	Interval time.Duration
+
+	// Timeout of a single probe, 5 seconds if zero
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	status map[string]EndpointStatus
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// Start probes the endpoints immediately and then every Interval in a
+// background goroutine until Stop is called.
+func (p *HealthProber) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done)
+}
+
+// Stop halts background probing and waits for an in-flight probe to finish.
+func (p *HealthProber) Stop() {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (p *HealthProber) run(stop, done chan struct{}) {
+	defer close(done)
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.Probe()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Probe checks every endpoint once and makes the first healthy endpoint
+// active if the currently active one is unhealthy.
+func (p *HealthProber) Probe() {
+	results := make(map[string]bool)
+	probe := func(u string) bool {
+		if healthy, ok := results[u]; ok {
+			return healthy
+		}
+		healthy := p.check(u)
+		results[u] = healthy
+		p.record(u, healthy)
+		return healthy
+	}
+	endpoints := p.Config.Endpoint.endpoints()
+	healthy := make([]bool, len(endpoints))
+	for i, e := range endpoints {
+		healthy[i] = true
+		for _, u := range []string{e.RequestTokenURL, e.AccessTokenURL} {
+			if u != "" && !probe(u) {
+				healthy[i] = false
+			}
+		}
+	}
+	if p.APIBaseURL != "" {
+		probe(p.APIBaseURL)
+	}
+	p.Config.preferHealthy(healthy)
+}
+
+func (p *HealthProber) record(u string, healthy bool) {
+	p.mu.Lock()
+	if p.status == nil {
+		p.status = make(map[string]EndpointStatus)
+	}
+	p.status[u] = EndpointStatus{URL: u, Healthy: healthy, Checked: time.Now()}
+	p.mu.Unlock()
+	p.Config.metrics().EndpointStatus(u, healthy)
+}
+
+func (p *HealthProber) check(u string) bool {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	ctx := p.Config.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequest("HEAD", u, nil)
+	if err != nil {
+		return false
+	}
+	res, err := p.Config.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return true
+}
+
+// Status returns the result of the most recent probe of each URL, ordered
+// by URL.
+func (p *HealthProber) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := make([]EndpointStatus, 0, len(p.status))
+	for _, s := range p.status {
+		status = append(status, s)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].URL < status[j].URL })
+	return status
+}
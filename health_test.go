@@ -0,0 +1,108 @@
+package oauth1
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type endpointStatusRecorder struct {
+	NopMetrics
+	mu     sync.Mutex
+	status map[string]bool
+}
+
+func (r *endpointStatusRecorder) EndpointStatus(url string, healthy bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status == nil {
+		r.status = make(map[string]bool)
+	}
+	r.status[url] = healthy
+}
+
+func TestHealthProber_Probe(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "HEAD", req.Method)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+	defer server.Close()
+	down := newClosedServerURL()
+
+	recorder := &endpointStatusRecorder{}
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: down + "/request_token",
+			AccessTokenURL:  down + "/access_token",
+			Fallbacks: []Endpoint{
+				{
+					RequestTokenURL: server.URL + "/request_token",
+					AccessTokenURL:  server.URL + "/access_token",
+				},
+			},
+		},
+		Metrics: recorder,
+	}
+	prober := &HealthProber{Config: config, APIBaseURL: server.URL}
+	prober.Probe()
+
+	assert.Equal(t, server.URL+"/request_token", config.activeEndpoint().RequestTokenURL)
+	assert.Equal(t, map[string]bool{
+		down + "/request_token":       false,
+		down + "/access_token":        false,
+		server.URL + "/request_token": true,
+		server.URL + "/access_token":  true,
+		server.URL:                    true,
+	}, recorder.status)
+	status := prober.Status()
+	if assert.Len(t, status, 5) {
+		for _, s := range status {
+			assert.Equal(t, recorder.status[s.URL], s.Healthy)
+		}
+	}
+}
+
+func TestHealthProber_KeepsHealthyActiveEndpoint(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL,
+			Fallbacks: []Endpoint{
+				{RequestTokenURL: server.URL + "/mirror"},
+			},
+		},
+	}
+	config.active = 1
+	prober := &HealthProber{Config: config}
+	prober.Probe()
+	assert.Equal(t, server.URL+"/mirror", config.activeEndpoint().RequestTokenURL)
+}
+
+func TestHealthProber_StartStop(t *testing.T) {
+	probed := make(chan struct{}, 1)
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case probed <- struct{}{}:
+		default:
+		}
+	})
+	defer server.Close()
+
+	prober := &HealthProber{
+		Config:   &Config{Endpoint: Endpoint{RequestTokenURL: server.URL}},
+		Interval: time.Millisecond,
+	}
+	prober.Start()
+	select {
+	case <-probed:
+	case <-time.After(time.Second):
+		t.Error("expected a probe after Start")
+	}
+	prober.Stop()
+	prober.Stop()
+}
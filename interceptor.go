@@ -0,0 +1,57 @@
+package oauth1
+
+import (
+	"net/http"
+
+	"github.com/ktnyt/oauth1/internal"
+)
+
+// Interceptor wraps the http.RoundTripper which carries token endpoint
+// requests, for example to add headers, use a custom TLS configuration or
+// record latencies. Interceptors apply to the requests made by RequestToken
+// and AccessToken only, never to requests made with a Client.
+type Interceptor func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as
+// an http.RoundTripper, which is convenient when writing an Interceptor.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// httpClient returns the Config's HTTPClient, or else the client of its
+// Context, with the Timeout applied if the client has none.
+func (c *Config) httpClient() *http.Client {
+	client := c.HTTPClient
+	if client == nil {
+		client = internal.ContextClient(c.Context)
+	}
+	if c.Timeout <= 0 || client.Timeout > 0 {
+		return client
+	}
+	timed := *client
+	timed.Timeout = c.Timeout
+	return &timed
+}
+
+// tokenClient returns the *http.Client for token endpoint requests with the
+// Interceptors applied around its Transport, the first Interceptor being
+// outermost.
+func (c *Config) tokenClient() *http.Client {
+	client := c.httpClient()
+	if len(c.Interceptors) == 0 {
+		return client
+	}
+	var transport http.RoundTripper = http.DefaultTransport
+	if client.Transport != nil {
+		transport = client.Transport
+	}
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		transport = c.Interceptors[i](transport)
+	}
+	intercepted := *client
+	intercepted.Transport = transport
+	return &intercepted
+}
@@ -0,0 +1,94 @@
+package oauth1
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigInterceptors(t *testing.T) {
+	data := url.Values{}
+	data.Add("oauth_token", "request_token")
+	data.Add("oauth_token_secret", "request_secret")
+	data.Add("oauth_callback_confirmed", "true")
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "outer,inner", req.Header.Get("X-Intercepted"))
+		w.Write([]byte(data.Encode()))
+	})
+	defer server.Close()
+
+	var order []string
+	intercept := func(name string) Interceptor {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				if seen := req.Header.Get("X-Intercepted"); seen != "" {
+					name = seen + "," + name
+				}
+				req.Header.Set("X-Intercepted", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	config := &Config{
+		Endpoint:     Endpoint{RequestTokenURL: server.URL},
+		Interceptors: []Interceptor{intercept("outer"), intercept("inner")},
+	}
+	requestToken, _, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+}
+
+func TestConfigInterceptors_NotAppliedToClient(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {})
+	defer server.Close()
+
+	intercepted := false
+	config := &Config{
+		Interceptors: []Interceptor{
+			func(next http.RoundTripper) http.RoundTripper {
+				intercepted = true
+				return next
+			},
+		},
+	}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+	assert.False(t, intercepted)
+}
+
+func TestConfigHTTPClient(t *testing.T) {
+	client := &http.Client{}
+	config := &Config{HTTPClient: client}
+	assert.True(t, client == config.tokenClient())
+
+	// the HTTPClient takes precedence over the Context's client
+	config.Context = context.WithValue(context.Background(), HTTPClient, &http.Client{})
+	assert.True(t, client == config.tokenClient())
+}
+
+func TestConfigTimeout(t *testing.T) {
+	config := &Config{Timeout: time.Second}
+	assert.Equal(t, time.Second, config.tokenClient().Timeout)
+	assert.Equal(t, time.Duration(0), http.DefaultClient.Timeout)
+
+	// a client's own timeout is kept
+	config.HTTPClient = &http.Client{Timeout: time.Minute}
+	assert.Equal(t, time.Minute, config.tokenClient().Timeout)
+}
+
+func TestConfigTimeout_TokenRequest(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{RequestTokenURL: server.URL}, Timeout: 20 * time.Millisecond}
+	_, _, err := config.RequestToken()
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultJanitorInterval is how often a Janitor started with a non-positive
+// interval prunes.
+const DefaultJanitorInterval = time.Minute
+
+// Janitor periodically prunes expired entries from an in-memory store in a
+// background goroutine. The zero value is stopped.
+type Janitor struct {
+	// mu is held across all of Start and Stop so that concurrent calls
+	// cannot leave a goroutine running unreferenced. The goroutine itself
+	// never takes it.
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start runs prune every interval, or every DefaultJanitorInterval if
+// interval is not positive, until stopped, replacing any running goroutine.
+func (j *Janitor) Start(interval time.Duration, prune func()) {
+	if interval <= 0 {
+		interval = DefaultJanitorInterval
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.halt()
+	stop, done := make(chan struct{}), make(chan struct{})
+	j.stop, j.done = stop, done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				prune()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the goroutine, if running, and waits for it to exit.
+func (j *Janitor) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.halt()
+}
+
+// halt is Stop with j.mu held.
+func (j *Janitor) halt() {
+	if j.stop != nil {
+		close(j.stop)
+		<-j.done
+		j.stop, j.done = nil, nil
+	}
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJanitor(t *testing.T) {
+	var j Janitor
+	var pruned int32
+	j.Start(time.Millisecond, func() { atomic.AddInt32(&pruned, 1) })
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&pruned) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	j.Stop()
+	assert.NotZero(t, atomic.LoadInt32(&pruned))
+	j.Stop()
+}
+
+func TestJanitor_Concurrent(t *testing.T) {
+	var j Janitor
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			j.Start(0, func() {})
+		}()
+		go func() {
+			defer wg.Done()
+			j.Stop()
+		}()
+	}
+	wg.Wait()
+	j.Stop()
+	assert.Nil(t, j.stop)
+}
@@ -0,0 +1,90 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+)
+
+// AuthorizeLoopback runs the three-legged flow for CLI applications: it
+// listens on a temporary localhost port used as the callback URL, obtains a
+// request token, calls open with the authorization URL, waits for the
+// provider to redirect the user's browser back and returns the access token.
+// open is typically OpenBrowser or a func printing the URL for the user. The
+// Config's CallbackURL is ignored. Cancel ctx to stop waiting.
+func (c *Config) AuthorizeLoopback(ctx context.Context, open func(authorizationURL string) error) (*Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+
+	loopback := c.Clone()
+	loopback.CallbackURL = fmt.Sprintf("http://%s/callback", listener.Addr())
+	token, secret, err := loopback.RequestTokenContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requestToken := &Token{Token: token, TokenSecret: secret}
+	authorizationURL, err := loopback.AuthorizationURL(requestToken.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	type callback struct {
+		verifier string
+		err      error
+	}
+	callbacks := make(chan callback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, req *http.Request) {
+		token, verifier, err := ParseAuthorizationCallback(req)
+		if err == nil && token != requestToken.Token {
+			err = errors.New("oauth1: Callback oauth_token does not match the request token")
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Authorization complete, you may close this window.")
+		}
+		select {
+		case callbacks <- callback{verifier, err}:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := open(authorizationURL.String()); err != nil {
+		return nil, err
+	}
+	select {
+	case cb := <-callbacks:
+		if cb.err != nil {
+			return nil, cb.err
+		}
+		token, _, err := loopback.accessToken(ctx, requestToken.Token, requestToken.TokenSecret, cb.verifier)
+		return token, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// OpenBrowser opens rawurl in the user's default browser.
+func OpenBrowser(rawurl string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", rawurl)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawurl)
+	default:
+		cmd = exec.Command("xdg-open", rawurl)
+	}
+	return cmd.Start()
+}
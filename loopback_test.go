@@ -0,0 +1,78 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoopbackServer returns a Config for a provider which authorizes every
+// request token immediately, redirecting to its oauth_callback.
+func newLoopbackServer(t *testing.T) *Config {
+	var callback string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/request_token", func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		callback, _ = url.QueryUnescape(params["oauth_callback"])
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"))
+	})
+	mux.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, req *http.Request) {
+		token := req.URL.Query().Get("oauth_token")
+		http.Redirect(w, req, callback+"?oauth_token="+token+"&oauth_verifier="+expectedVerifier, http.StatusFound)
+	})
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, expectedVerifier, params["oauth_verifier"])
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=access_token&oauth_token_secret=access_secret"))
+	})
+	server := newMockServer(mux.ServeHTTP)
+	t.Cleanup(server.Close)
+	return &Config{
+		ConsumerKey: "consumer_key",
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL + "/oauth/request_token",
+			AuthorizeURL:    server.URL + "/oauth/authorize",
+			AccessTokenURL:  server.URL + "/oauth/access_token",
+		},
+	}
+}
+
+func TestConfigAuthorizeLoopback(t *testing.T) {
+	config := newLoopbackServer(t)
+	browse := func(authorizationURL string) error {
+		go func() {
+			res, err := http.Get(authorizationURL)
+			if assert.Nil(t, err) {
+				assert.Equal(t, http.StatusOK, res.StatusCode)
+				res.Body.Close()
+			}
+		}()
+		return nil
+	}
+	token, err := config.AuthorizeLoopback(context.Background(), browse)
+	assert.Nil(t, err)
+	assert.Equal(t, &Token{Token: "access_token", TokenSecret: "access_secret"}, token)
+	assert.Empty(t, config.CallbackURL)
+}
+
+func TestConfigAuthorizeLoopback_Canceled(t *testing.T) {
+	config := newLoopbackServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := config.AuthorizeLoopback(ctx, func(string) error { return nil })
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestConfigAuthorizeLoopback_OpenError(t *testing.T) {
+	config := newLoopbackServer(t)
+	openErr := errors.New("no browser")
+	_, err := config.AuthorizeLoopback(context.Background(), func(string) error { return openErr })
+	assert.Equal(t, openErr, err)
+}
@@ -0,0 +1,75 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// LTILaunch is a verified LTI 1.1 basic launch request of a tool consumer,
+// such as a learning management system, to a tool.
+type LTILaunch struct {
+	// ConsumerKey of the tool consumer
+	ConsumerKey string
+
+	ResourceLinkID string
+	UserID         string
+	Roles          []string
+
+	// Params are the launch parameters, without oauth_* parameters
+	Params url.Values
+}
+
+// errLTIMethod rejects launch requests which are not POSTed.
+var errLTIMethod = errors.New("oauth1: LTI launch requests must be POSTed")
+
+// VerifyLTILaunch verifies an LTI 1.1 basic launch request: a form POSTed
+// by a tool consumer with the OAuth protocol parameters in the body, signed
+// without a token; requests of other methods are rejected. It returns the
+// launch parameters. The body is read and replaced with an equivalent
+// reader. Errors are *VerificationErrors.
+// See https://www.imsglobal.org/specs/ltiv1p1/implementation-guide.
+func (v *Verifier) VerifyLTILaunch(req *http.Request) (*LTILaunch, error) {
+	if req.Method != "POST" {
+		return nil, verificationError("parameter_rejected", errLTIMethod)
+	}
+	params, err := signing{}.requestParams(req)
+	if err != nil {
+		return nil, verificationError("parameter_rejected", err)
+	}
+	oauthParams := splitOAuthParams(params)
+	if _, err := v.verify(req, oauthParams, params); err != nil {
+		return nil, err
+	}
+	// params now hold the signed oauth_* parameters too
+	for name := range oauthParams {
+		delete(params, name)
+	}
+	if params.Get("lti_message_type") != "basic-lti-launch-request" {
+		return nil, rejectedParameter("lti_message_type", nil)
+	}
+	var absent []string
+	for _, name := range []string{"lti_version", "resource_link_id"} {
+		if params.Get(name) == "" {
+			absent = append(absent, name)
+		}
+	}
+	if len(absent) > 0 {
+		e := verificationError("parameter_absent", nil)
+		e.Problem.ParametersAbsent = absent
+		return nil, e
+	}
+	launch := &LTILaunch{
+		ConsumerKey:    oauthParams.Get("oauth_consumer_key"),
+		ResourceLinkID: params.Get("resource_link_id"),
+		UserID:         params.Get("user_id"),
+		Params:         params,
+	}
+	for _, role := range strings.Split(params.Get("roles"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			launch.Roles = append(launch.Roles, role)
+		}
+	}
+	return launch, nil
+}
@@ -0,0 +1,78 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFormRequest(t *testing.T, body string) *http.Request {
+	req, err := http.NewRequest("POST", "https://tool.example.com/launch", strings.NewReader(body))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// signLTILaunch returns the body of a launch form POST signed by config
+// with the protocol parameters in the body.
+func signLTILaunch(t *testing.T, config *Config, form url.Values) string {
+	signed := newFormRequest(t, form.Encode())
+	assert.Nil(t, config.SignRequest(signed, nil))
+	oauthParams, err := ParseOAuthHeader(signed.Header.Get("Authorization"))
+	assert.Nil(t, err)
+	body := url.Values{}
+	for name, values := range form {
+		body[name] = values
+	}
+	for name, values := range oauthParams {
+		body[name] = values
+	}
+	return body.Encode()
+}
+
+func TestVerifier_VerifyLTILaunch(t *testing.T) {
+	config := &Config{ConsumerKey: "lms_key", ConsumerSecret: "lms secret"}
+	v := &Verifier{ConsumerSecret: lookupSecret(map[string]string{"lms_key": "lms secret"})}
+	form := url.Values{
+		"lti_message_type": {"basic-lti-launch-request"},
+		"lti_version":      {"LTI-1p0"},
+		"resource_link_id": {"course-1/assignment-2"},
+		"user_id":          {"student-3"},
+		"roles":            {"Learner, urn:lti:instrole:ims/lis/Student"},
+	}
+	launch, err := v.VerifyLTILaunch(newFormRequest(t, signLTILaunch(t, config, form)))
+	assert.Nil(t, err)
+	assert.Equal(t, "lms_key", launch.ConsumerKey)
+	assert.Equal(t, "course-1/assignment-2", launch.ResourceLinkID)
+	assert.Equal(t, "student-3", launch.UserID)
+	assert.Equal(t, []string{"Learner", "urn:lti:instrole:ims/lis/Student"}, launch.Roles)
+	assert.Equal(t, form, launch.Params)
+
+	// tampered launch parameters
+	body := strings.Replace(signLTILaunch(t, config, form), "student-3", "teacher-1", 1)
+	_, err = v.VerifyLTILaunch(newFormRequest(t, body))
+	assertProblem(t, err, "signature_invalid")
+
+	form.Set("lti_message_type", "ContentItemSelectionRequest")
+	_, err = v.VerifyLTILaunch(newFormRequest(t, signLTILaunch(t, config, form)))
+	verr := assertProblem(t, err, "parameter_rejected")
+	assert.Equal(t, []string{"lti_message_type"}, verr.Problem.ParametersRejected)
+
+	form.Set("lti_message_type", "basic-lti-launch-request")
+	form.Del("resource_link_id")
+	_, err = v.VerifyLTILaunch(newFormRequest(t, signLTILaunch(t, config, form)))
+	verr = assertProblem(t, err, "parameter_absent")
+	assert.Equal(t, []string{"resource_link_id"}, verr.Problem.ParametersAbsent)
+
+	// launches are POSTed, even when signed for another method
+	form.Set("resource_link_id", "course-1/assignment-2")
+	req, err := http.NewRequest("GET", "https://tool.example.com/launch?"+signLTILaunch(t, config, form), nil)
+	assert.Nil(t, err)
+	_, err = v.VerifyLTILaunch(req)
+	assertProblem(t, err, "parameter_rejected")
+	assert.True(t, errors.Is(err, errLTIMethod))
+}
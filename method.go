@@ -0,0 +1,33 @@
+package oauth1
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// signatureMethodKey is the context key of the HTTP method a request is
+// signed as.
+type signatureMethodKey struct{}
+
+// SignAsMethod returns a shallow copy of req which is signed as if its HTTP
+// method were method, for providers behind gateways which tunnel methods,
+// e.g. a POST sent as a GET with an X-HTTP-Method-Override header and signed
+// as the POST. The request is sent with its own method.
+func SignAsMethod(req *http.Request, method string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), signatureMethodKey{}, method))
+}
+
+// signatureHTTPMethod returns the HTTP method of the signature base string
+// of req, uppercased: the method given to SignAsMethod, else req.Method, or
+// GET if empty as for net/http.
+func signatureHTTPMethod(req *http.Request) string {
+	method := req.Method
+	if override, ok := req.Context().Value(signatureMethodKey{}).(string); ok {
+		method = override
+	}
+	if method == "" {
+		return "GET"
+	}
+	return strings.ToUpper(method)
+}
@@ -0,0 +1,59 @@
+package oauth1
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureHTTPMethod(t *testing.T) {
+	for _, method := range []string{"get", "Patch", "PROPFIND", "mkcol", "X-CUSTOM"} {
+		req, err := http.NewRequest(method, "https://api.example.com/dav", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, strings.ToUpper(method), signatureHTTPMethod(req))
+	}
+	req := &http.Request{}
+	assert.Equal(t, "GET", signatureHTTPMethod(req))
+}
+
+func TestSignAsMethod(t *testing.T) {
+	s := signing{consumerKey: "consumer_key", consumerSecret: "consumer_secret"}
+	st := stamp{"nonce", time.Unix(1318467427, 0)}
+	post, err := http.NewRequest("POST", "https://api.example.com/1/items", nil)
+	assert.Nil(t, err)
+	expected, err := s.authorizationHeader(post, "token_secret", nil, st)
+	assert.Nil(t, err)
+
+	get, err := http.NewRequest("GET", "https://api.example.com/1/items", nil)
+	assert.Nil(t, err)
+	tunneled := SignAsMethod(get, "post")
+	assert.Equal(t, "GET", tunneled.Method)
+	header, err := s.authorizationHeader(tunneled, "token_secret", nil, st)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, header)
+}
+
+func TestConfigClient_SignAsMethod(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		signed, err := http.NewRequest("DELETE", "http://"+req.Host+req.URL.RequestURI(), nil)
+		assert.Nil(t, err)
+		header, err := config.signing().authorizationHeader(signed, "token_secret",
+			map[string][]string{"oauth_token": {"token"}},
+			stamp{params["oauth_nonce"], time.Unix(mustParseInt(t, params["oauth_timestamp"]), 0)})
+		assert.Nil(t, err)
+		assert.Equal(t, parseOAuthParamsOrFail(t, header)["oauth_signature"], params["oauth_signature"])
+	})
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/1/items/1", nil)
+	assert.Nil(t, err)
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+	_, err = config.Client(NoContext, "token", "token_secret").Do(SignAsMethod(req, "DELETE"))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,49 @@
+package oauth1
+
+import "time"
+
+// Metrics receives measurements from Config and Transport, for example to
+// export them to a monitoring system. Implementations must be safe for
+// concurrent use and should embed NopMetrics, so that they keep compiling
+// when methods are added.
+type Metrics interface {
+	// EndpointStatus reports whether the endpoint URL responded to the most
+	// recent health probe.
+	EndpointStatus(url string, healthy bool)
+
+	// TokenRequest reports a request to the token endpoint url, such as a
+	// RequestToken or AccessToken call, with its duration (retries
+	// included) and error, nil if it succeeded.
+	TokenRequest(url string, duration time.Duration, err error)
+
+	// RequestSigned reports a request to host signed by a Transport, with
+	// the error which prevented signing it, if any.
+	RequestSigned(host string, err error)
+}
+
+// NopMetrics is a Metrics discarding all measurements. It is used when none
+// is configured.
+type NopMetrics struct{}
+
+// EndpointStatus does nothing.
+func (NopMetrics) EndpointStatus(url string, healthy bool) {}
+
+// TokenRequest does nothing.
+func (NopMetrics) TokenRequest(url string, duration time.Duration, err error) {}
+
+// RequestSigned does nothing.
+func (NopMetrics) RequestSigned(host string, err error) {}
+
+func (c *Config) metrics() Metrics {
+	if c.Metrics != nil {
+		return c.Metrics
+	}
+	return NopMetrics{}
+}
+
+func (t *Transport) metrics() Metrics {
+	if t.Metrics != nil {
+		return t.Metrics
+	}
+	return NopMetrics{}
+}
@@ -0,0 +1,75 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type metricsRecorder struct {
+	NopMetrics
+	mu            sync.Mutex
+	tokenRequests []error
+	tokenURLs     []string
+	signed        []string
+	signErrs      []error
+}
+
+func (r *metricsRecorder) TokenRequest(url string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokenURLs = append(r.tokenURLs, url)
+	r.tokenRequests = append(r.tokenRequests, err)
+}
+
+func (r *metricsRecorder) RequestSigned(host string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.signed = append(r.signed, host)
+	r.signErrs = append(r.signErrs, err)
+}
+
+func TestMetrics_TokenRequest(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	recorder := &metricsRecorder{}
+	config := &Config{Endpoint: Endpoint{RequestTokenURL: server.URL}, Metrics: recorder}
+	_, _, err := config.RequestToken()
+	assert.Error(t, err)
+	assert.Equal(t, []string{server.URL}, recorder.tokenURLs)
+	var tokenErr *TokenRequestError
+	if assert.Len(t, recorder.tokenRequests, 1) {
+		assert.True(t, errors.As(recorder.tokenRequests[0], &tokenErr))
+	}
+}
+
+func TestMetrics_RequestSigned(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {})
+	defer server.Close()
+
+	recorder := &metricsRecorder{}
+	config := &Config{ConsumerKey: "consumer_key", Metrics: recorder}
+	client := config.Client(NoContext, "token", "secret")
+	_, err := client.Get(server.URL)
+	assert.Nil(t, err)
+
+	// an unparseable form body fails signing
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("%gh&%ij"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = client.Do(req)
+	assert.Error(t, err)
+
+	host := server.Listener.Addr().String()
+	assert.Equal(t, []string{host, host}, recorder.signed)
+	assert.Nil(t, recorder.signErrs[0])
+	assert.Error(t, recorder.signErrs[1])
+}
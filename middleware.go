@@ -0,0 +1,92 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// verifiedKey is the context key of the credentials of a request verified
+// by RequireOAuth1.
+type verifiedKey struct{}
+
+// verifiedCredentials are the consumer key and token of a verified request.
+type verifiedCredentials struct {
+	consumerKey string
+	token       string
+}
+
+// A MiddlewareOption configures the middleware returned by RequireOAuth1.
+type MiddlewareOption func(*middleware)
+
+// WithVerifier sets the Verifier of requests. It must be given.
+func WithVerifier(v *Verifier) MiddlewareOption {
+	return func(m *middleware) {
+		m.verifier = v
+	}
+}
+
+// WithRealm sets the realm of the WWW-Authenticate challenge of rejected
+// requests.
+func WithRealm(realm string) MiddlewareOption {
+	return func(m *middleware) {
+		m.realm = realm
+	}
+}
+
+type middleware struct {
+	next     http.Handler
+	verifier *Verifier
+	realm    string
+}
+
+// RequireOAuth1 returns a handler serving requests with next only if they
+// are signed by a consumer as verified by the Verifier given with
+// WithVerifier. The consumer key and token of verified requests are
+// available to next with ConsumerKeyFromContext and TokenFromContext.
+// Rejected requests are answered with 400 Bad Request or 401 Unauthorized
+// and an OAuth WWW-Authenticate challenge reporting the problem. Errors of
+// the Verifier's NonceStore are answered with 500 Internal Server Error.
+func RequireOAuth1(next http.Handler, opts ...MiddlewareOption) http.Handler {
+	m := &middleware{next: next}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.verifier == nil {
+		panic("oauth1: RequireOAuth1 needs WithVerifier")
+	}
+	return m
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	params, err := m.verifier.Verify(req)
+	if err != nil {
+		var verr *VerificationError
+		if !errors.As(err, &verr) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		WriteProblem(w, m.realm, &verr.Problem)
+		return
+	}
+	ctx := context.WithValue(req.Context(), verifiedKey{}, verifiedCredentials{
+		consumerKey: params.Get("oauth_consumer_key"),
+		token:       params.Get("oauth_token"),
+	})
+	m.next.ServeHTTP(w, req.WithContext(ctx))
+}
+
+// ConsumerKeyFromContext returns the consumer key of the request verified by
+// RequireOAuth1 whose context is ctx.
+func ConsumerKeyFromContext(ctx context.Context) (string, bool) {
+	verified, ok := ctx.Value(verifiedKey{}).(verifiedCredentials)
+	return verified.consumerKey, ok
+}
+
+// TokenFromContext returns the oauth_token of the request verified by
+// RequireOAuth1 whose context is ctx. It is empty for requests of
+// 2-legged consumers.
+func TokenFromContext(ctx context.Context) (string, bool) {
+	verified, ok := ctx.Value(verifiedKey{}).(verifiedCredentials)
+	return verified.token, ok
+}
@@ -0,0 +1,53 @@
+package oauth1
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireOAuth1(t *testing.T) {
+	var consumerKey, token string
+	handler := RequireOAuth1(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var ok bool
+		consumerKey, ok = ConsumerKeyFromContext(req.Context())
+		assert.True(t, ok)
+		token, _ = TokenFromContext(req.Context())
+	}), WithVerifier(newTestVerifier()), WithRealm("Example"))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	res, err := config.Client(NoContext, "token", "token&secret").Get(server.URL + "/1/feed")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "consumer_key", consumerKey)
+	assert.Equal(t, "token", token)
+
+	res, err = config.Client(NoContext, "token", "guess").Get(server.URL + "/1/feed")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, `OAuth realm="Example", oauth_problem="signature_invalid"`, res.Header.Get("WWW-Authenticate"))
+
+	res, err = http.Get(server.URL + "/1/feed")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	problem := parseProblem(res.Header, nil)
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, "parameter_absent", problem.Problem)
+		assert.Contains(t, problem.ParametersAbsent, "oauth_consumer_key")
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, problem, parseProblem(http.Header{}, body))
+}
+
+func TestFromContext_Unverified(t *testing.T) {
+	_, ok := ConsumerKeyFromContext(NoContext)
+	assert.False(t, ok)
+	_, ok = TokenFromContext(NoContext)
+	assert.False(t, ok)
+}
@@ -0,0 +1,112 @@
+package oauth1
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+)
+
+// A Noncer generates oauth_nonce values. Implementations must be safe for
+// concurrent use.
+type Noncer interface {
+	Nonce() string
+}
+
+// newNonce returns a nonce from n, or from a default RandomNoncer if n is
+// nil.
+func newNonce(n Noncer) string {
+	atomic.AddInt64(&stats.nonceGenerations, 1)
+	if n == nil {
+		return RandomNoncer{}.Nonce()
+	}
+	return n.Nonce()
+}
+
+const (
+	// DefaultNonceLength is the length of nonces generated by a
+	// RandomNoncer without a Length.
+	DefaultNonceLength = 32
+
+	// AlphanumericAlphabet is the alphabet of nonces generated by a
+	// RandomNoncer without an Alphabet.
+	AlphanumericAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// RandomNoncer generates nonces of Length characters drawn uniformly from
+// Alphabet using crypto/rand. The zero value generates DefaultNonceLength
+// alphanumeric characters, which every provider accepts; set Length and
+// Alphabet for providers restricting nonces further.
+type RandomNoncer struct {
+	// Length of nonces, DefaultNonceLength if zero
+	Length int
+
+	// Alphabet of nonce characters (single bytes, at most 256),
+	// AlphanumericAlphabet if empty
+	Alphabet string
+}
+
+// Nonce returns a random nonce. It panics if Alphabet is longer than 256
+// bytes or crypto/rand fails, as no safe nonce can be generated then.
+func (n RandomNoncer) Nonce() string {
+	length, alphabet := n.Length, n.Alphabet
+	if length <= 0 {
+		length = DefaultNonceLength
+	}
+	if alphabet == "" {
+		alphabet = AlphanumericAlphabet
+	}
+	if len(alphabet) > 256 {
+		panic("oauth1: RandomNoncer Alphabet exceeds 256 bytes")
+	}
+	// reject bytes beyond the largest multiple of the alphabet size to avoid
+	// modulo bias
+	limit := 256 - 256%len(alphabet)
+	nonce := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(nonce) < length {
+		if _, err := rand.Read(buf); err != nil {
+			panic("oauth1: Failed to read random nonce: " + err.Error())
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(nonce) < length {
+				nonce = append(nonce, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(nonce)
+}
+
+// FixedNoncer always returns itself as the nonce. It makes signatures
+// reproducible in tests and must never be used against a real provider,
+// which rejects repeated nonces.
+type FixedNoncer string
+
+// Nonce returns n.
+func (n FixedNoncer) Nonce() string {
+	return string(n)
+}
+
+// CounterNoncer generates nonces combining a random per-instance prefix
+// with a monotonically increasing counter. Nonces from one CounterNoncer
+// never repeat, making it suitable for clients signing millions of requests
+// per second; the counter is updated atomically without locks.
+type CounterNoncer struct {
+	prefix  string
+	counter uint64
+}
+
+// NewCounterNoncer returns a CounterNoncer with a fresh random prefix. It
+// panics if crypto/rand fails.
+func NewCounterNoncer() *CounterNoncer {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic("oauth1: Failed to read random nonce prefix: " + err.Error())
+	}
+	return &CounterNoncer{prefix: hex.EncodeToString(b)}
+}
+
+// Nonce returns the prefix followed by the next counter value in base 36.
+func (n *CounterNoncer) Nonce() string {
+	return n.prefix + strconv.FormatUint(atomic.AddUint64(&n.counter, 1), 36)
+}
@@ -0,0 +1,105 @@
+package oauth1
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterNoncer(t *testing.T) {
+	noncer := NewCounterNoncer()
+	assert.Equal(t, noncer.prefix+"1", noncer.Nonce())
+	assert.Equal(t, noncer.prefix+"2", noncer.Nonce())
+	assert.NotEqual(t, noncer.prefix, NewCounterNoncer().prefix)
+}
+
+func TestCounterNoncer_Concurrent(t *testing.T) {
+	const goroutines, perGoroutine = 8, 1000
+	noncer := NewCounterNoncer()
+	nonces := make(chan string, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				nonces <- noncer.Nonce()
+			}
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+	seen := make(map[string]bool)
+	for nonce := range nonces {
+		assert.False(t, seen[nonce], "duplicate nonce %s", nonce)
+		seen[nonce] = true
+	}
+	assert.Len(t, seen, goroutines*perGoroutine)
+}
+
+func TestConfigClient_Noncer(t *testing.T) {
+	noncer := NewCounterNoncer()
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, noncer.prefix+"1", params["oauth_nonce"])
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", Noncer: noncer}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestConfigClient_FixedNoncer(t *testing.T) {
+	requests := 0
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "fixed_nonce", params["oauth_nonce"])
+		requests++
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", Noncer: FixedNoncer("fixed_nonce")}
+	client := config.Client(NoContext, "token", "secret")
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(server.URL)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 2, requests)
+}
+
+func TestRandomNoncer(t *testing.T) {
+	nonce := RandomNoncer{}.Nonce()
+	assert.Len(t, nonce, DefaultNonceLength)
+	for _, c := range nonce {
+		assert.Contains(t, AlphanumericAlphabet, string(c))
+	}
+	assert.NotEqual(t, nonce, RandomNoncer{}.Nonce())
+
+	noncer := RandomNoncer{Length: 8, Alphabet: "0123456789"}
+	for i := 0; i < 100; i++ {
+		nonce := noncer.Nonce()
+		assert.Len(t, nonce, 8)
+		for _, c := range nonce {
+			assert.True(t, c >= '0' && c <= '9', "unexpected character %q", c)
+		}
+	}
+
+	assert.Len(t, RandomNoncer{Alphabet: strings.Repeat("a", 256)}.Nonce(), DefaultNonceLength)
+	assert.Panics(t, func() { RandomNoncer{Alphabet: strings.Repeat("a", 257)}.Nonce() })
+}
+
+func TestConfigClient_DefaultNoncer(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Len(t, params["oauth_nonce"], DefaultNonceLength)
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key"}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+}
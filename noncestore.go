@@ -0,0 +1,268 @@
+package oauth1
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/ktnyt/oauth1/internal"
+)
+
+// DefaultNonceStoreSize is how many nonces a MemoryNonceStore remembers when
+// no size is given.
+const DefaultNonceStoreSize = 100000
+
+// A NonceStore remembers the nonces of verified requests so a Verifier can
+// reject replayed requests. Keys combine the consumer key, token, timestamp
+// and nonce of a request, which must be unique per RFC 5849 3.3.
+// Implementations must be safe for concurrent use.
+type NonceStore interface {
+	// Use remembers key for ttl and reports whether it was unused, that is
+	// not remembered or expired. Checking and remembering key must be one
+	// atomic step, so that concurrent replays of a request cannot both
+	// find its nonce unused.
+	Use(key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore is a NonceStore keeping nonces in memory. Beyond its size
+// the least recently remembered nonces are evicted, so the size should
+// exceed the number of requests expected within the timestamp window; call
+// StartJanitor to prune expired nonces in the background rather than only on
+// eviction. A MemoryNonceStore is safe for concurrent use.
+type MemoryNonceStore struct {
+	size int
+
+	mu      sync.Mutex
+	lru     *list.List
+	nonces  map[string]*list.Element
+	janitor internal.Janitor
+	now     func() time.Time
+}
+
+type storedNonce struct {
+	key     string
+	expires time.Time
+}
+
+// NewMemoryNonceStore returns a MemoryNonceStore remembering at most size
+// nonces, DefaultNonceStoreSize if zero.
+func NewMemoryNonceStore(size int) *MemoryNonceStore {
+	if size <= 0 {
+		size = DefaultNonceStoreSize
+	}
+	return &MemoryNonceStore{
+		size:   size,
+		lru:    list.New(),
+		nonces: make(map[string]*list.Element),
+	}
+}
+
+// Use remembers key for ttl, unless it is remembered already, and reports
+// whether it was unused, under a single lock.
+func (s *MemoryNonceStore) Use(key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.check(key) {
+		return false, nil
+	}
+	s.remember(key, ttl)
+	return true, nil
+}
+
+// Check reports whether key was remembered and has not expired yet.
+func (s *MemoryNonceStore) Check(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.check(key), nil
+}
+
+// Remember stores key for ttl, evicting the least recently remembered nonce
+// if the store is full.
+func (s *MemoryNonceStore) Remember(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remember(key, ttl)
+	return nil
+}
+
+func (s *MemoryNonceStore) check(key string) bool {
+	e, ok := s.nonces[key]
+	if !ok {
+		return false
+	}
+	if !s.clock().Before(e.Value.(*storedNonce).expires) {
+		s.remove(e)
+		return false
+	}
+	return true
+}
+
+func (s *MemoryNonceStore) remember(key string, ttl time.Duration) {
+	expires := s.clock().Add(ttl)
+	if e, ok := s.nonces[key]; ok {
+		e.Value.(*storedNonce).expires = expires
+		s.lru.MoveToFront(e)
+		return
+	}
+	s.nonces[key] = s.lru.PushFront(&storedNonce{key: key, expires: expires})
+	if s.lru.Len() > s.size {
+		s.remove(s.lru.Back())
+	}
+}
+
+// Len returns the number of remembered nonces, including expired ones which
+// have not been pruned yet.
+func (s *MemoryNonceStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// Prune removes expired nonces.
+func (s *MemoryNonceStore) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock()
+	for e := s.lru.Back(); e != nil; {
+		prev := e.Prev()
+		if !now.Before(e.Value.(*storedNonce).expires) {
+			s.remove(e)
+		}
+		e = prev
+	}
+}
+
+// StartJanitor prunes expired nonces every interval, or every minute if
+// interval is not positive, in a background goroutine until Stop is called.
+func (s *MemoryNonceStore) StartJanitor(interval time.Duration) {
+	s.janitor.Start(interval, s.Prune)
+}
+
+// Stop stops the janitor goroutine, if running.
+func (s *MemoryNonceStore) Stop() {
+	s.janitor.Stop()
+}
+
+func (s *MemoryNonceStore) remove(e *list.Element) {
+	s.lru.Remove(e)
+	delete(s.nonces, e.Value.(*storedNonce).key)
+}
+
+func (s *MemoryNonceStore) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// ShardedNonceStore is a NonceStore spreading nonces over several
+// MemoryNonceStores by hash of their key, so verifying many requests
+// concurrently in a single process contends on one shard lock each rather
+// than on a global lock. A ShardedNonceStore is safe for concurrent use.
+type ShardedNonceStore struct {
+	shards  []*MemoryNonceStore
+	janitor internal.Janitor
+}
+
+// NewShardedNonceStore returns a ShardedNonceStore of shards
+// MemoryNonceStores remembering at most size nonces in total,
+// DefaultNonceStoreSize if zero.
+func NewShardedNonceStore(shards, size int) *ShardedNonceStore {
+	if shards <= 0 {
+		shards = 1
+	}
+	if size <= 0 {
+		size = DefaultNonceStoreSize
+	}
+	s := &ShardedNonceStore{shards: make([]*MemoryNonceStore, shards)}
+	for i := range s.shards {
+		s.shards[i] = NewMemoryNonceStore((size + shards - 1) / shards)
+	}
+	return s
+}
+
+// Use remembers key for ttl in its shard, unless it is remembered already,
+// and reports whether it was unused.
+func (s *ShardedNonceStore) Use(key string, ttl time.Duration) (bool, error) {
+	return s.shard(key).Use(key, ttl)
+}
+
+// Check reports whether key was remembered and has not expired yet.
+func (s *ShardedNonceStore) Check(key string) (bool, error) {
+	return s.shard(key).Check(key)
+}
+
+// Remember stores key for ttl in its shard.
+func (s *ShardedNonceStore) Remember(key string, ttl time.Duration) error {
+	return s.shard(key).Remember(key, ttl)
+}
+
+// Len returns the number of remembered nonces over all shards.
+func (s *ShardedNonceStore) Len() int {
+	n := 0
+	for _, shard := range s.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Prune removes expired nonces from all shards.
+func (s *ShardedNonceStore) Prune() {
+	for _, shard := range s.shards {
+		shard.Prune()
+	}
+}
+
+// StartJanitor prunes expired nonces every interval, or every minute if
+// interval is not positive, in a background goroutine until Stop is called.
+func (s *ShardedNonceStore) StartJanitor(interval time.Duration) {
+	s.janitor.Start(interval, s.Prune)
+}
+
+// Stop stops the janitor goroutine, if running.
+func (s *ShardedNonceStore) Stop() {
+	s.janitor.Stop()
+}
+
+func (s *ShardedNonceStore) shard(key string) *MemoryNonceStore {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// KeyValueStore is the minimal subset of a key-value store with expiring
+// entries, such as Redis or memcached, which nonces need: the presence of
+// keys, without values. It is shared by the replicas of a provider.
+// Implementations must be safe for concurrent use.
+type KeyValueStore interface {
+	// Exists reports whether key is stored and has not expired, like
+	// Redis EXISTS.
+	Exists(key string) (bool, error)
+
+	// SetNX stores key, expiring after ttl, unless it is stored already,
+	// and reports whether it was stored. Checking and storing key must be
+	// one atomic step, like Redis SET with NX or memcached add.
+	SetNX(key string, ttl time.Duration) (bool, error)
+}
+
+// KeyValueNonceStore is a NonceStore remembering nonces in a KeyValueStore,
+// letting every replica of a provider reject nonces used at any other.
+type KeyValueNonceStore struct {
+	// Store holding the nonces
+	Store KeyValueStore
+
+	// Prefix prepended to nonce keys, e.g. to share Store with other data
+	Prefix string
+}
+
+// Use stores key for ttl with SetNX, unless it is stored already, and
+// reports whether it was unused.
+func (s KeyValueNonceStore) Use(key string, ttl time.Duration) (bool, error) {
+	return s.Store.SetNX(s.Prefix+key, ttl)
+}
+
+// Check reports whether key is stored.
+func (s KeyValueNonceStore) Check(key string) (bool, error) {
+	return s.Store.Exists(s.Prefix + key)
+}
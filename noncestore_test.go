@@ -0,0 +1,116 @@
+package oauth1
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	store := NewMemoryNonceStore(2)
+	used, err := store.Check("a")
+	assert.Nil(t, err)
+	assert.False(t, used)
+
+	assert.Nil(t, store.Remember("a", time.Minute))
+	assert.Nil(t, store.Remember("b", time.Minute))
+	used, _ = store.Check("a")
+	assert.True(t, used)
+
+	// a was remembered least recently and is evicted for c
+	assert.Nil(t, store.Remember("c", time.Minute))
+	assert.Equal(t, 2, store.Len())
+	used, _ = store.Check("a")
+	assert.False(t, used)
+	used, _ = store.Check("b")
+	assert.True(t, used)
+}
+
+func TestMemoryNonceStore_Use(t *testing.T) {
+	store := NewMemoryNonceStore(0)
+	var wg sync.WaitGroup
+	var unused int32
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := store.Use("a", time.Minute)
+			assert.Nil(t, err)
+			if ok {
+				atomic.AddInt32(&unused, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	// exactly one of the concurrent replays finds the nonce unused
+	assert.Equal(t, int32(1), unused)
+}
+
+func TestMemoryNonceStore_TTL(t *testing.T) {
+	now := time.Unix(1318467427, 0)
+	store := NewMemoryNonceStore(0)
+	store.now = func() time.Time { return now }
+	assert.Nil(t, store.Remember("a", time.Minute))
+	assert.Nil(t, store.Remember("b", 2*time.Minute))
+
+	now = now.Add(time.Minute)
+	used, _ := store.Check("a")
+	assert.False(t, used)
+	used, _ = store.Check("b")
+	assert.True(t, used)
+
+	now = now.Add(time.Minute)
+	store.Prune()
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestShardedNonceStore(t *testing.T) {
+	store := NewShardedNonceStore(4, 0)
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	for _, key := range keys {
+		assert.Nil(t, store.Remember(key, time.Minute))
+	}
+	assert.Equal(t, len(keys), store.Len())
+	for _, key := range keys {
+		used, err := store.Check(key)
+		assert.Nil(t, err)
+		assert.True(t, used, key)
+	}
+	used, _ := store.Check("z")
+	assert.False(t, used)
+}
+
+// mapKeyValueStore is a KeyValueStore ignoring expiry.
+type mapKeyValueStore map[string]bool
+
+func (m mapKeyValueStore) Exists(key string) (bool, error) {
+	return m[key], nil
+}
+
+func (m mapKeyValueStore) SetNX(key string, ttl time.Duration) (bool, error) {
+	if _, ok := m[key]; ok {
+		return false, nil
+	}
+	m[key] = true
+	return true, nil
+}
+
+func TestKeyValueNonceStore(t *testing.T) {
+	kv := mapKeyValueStore{}
+	store := KeyValueNonceStore{Store: kv, Prefix: "nonce:"}
+	unused, err := store.Use("a", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, unused)
+	assert.Contains(t, kv, "nonce:a")
+
+	// another replica sharing the store sees the nonce
+	unused, err = KeyValueNonceStore{Store: kv, Prefix: "nonce:"}.Use("a", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, unused)
+	used, err := store.Check("a")
+	assert.Nil(t, err)
+	assert.True(t, used)
+}
@@ -2,15 +2,10 @@ package oauth1
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/md5"
-	"crypto/sha1"
-	"encoding/base64"
-	"errors"
-	"fmt"
+	"context"
 	"io"
 	"io/ioutil"
-	"math/rand"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -18,11 +13,10 @@ import (
 	"time"
 
 	"github.com/ktnyt/oauth1/internal"
-	"golang.org/x/net/context"
 )
 
 // NoContext is the default context you should supply if not using
-// your own context.Context (see https://golang.org/x/net/context).
+// your own context.Context.
 //
 // Deprecated: Use context.Background() or context.TODO() instead.
 var NoContext = context.TODO()
@@ -33,17 +27,90 @@ type Config struct {
 	// Context
 	Context context.Context
 
+	// HTTPClient makes token endpoint requests. If nil, the client
+	// associated with the Context is used, else http.DefaultClient
+	HTTPClient *http.Client
+
+	// Timeout of token endpoint requests made with an HTTPClient which has
+	// no Timeout of its own, none if zero
+	Timeout time.Duration
+
 	// Consumer Key (Client Identifier)
 	ConsumerKey string
 
 	// Consumer Secret (Client Shared-Secret)
 	ConsumerSecret string
 
+	// SecretProvider supplies the consumer secret at signing time in place
+	// of ConsumerSecret if non-nil
+	SecretProvider SecretProvider
+
 	// Callback URL
 	CallbackURL string
 
 	// Provider Endpoint specifying OAuth1 endpoint URLs
 	Endpoint Endpoint
+
+	// Protocol revision the provider implements, OAuth10a if zero
+	Protocol Protocol
+
+	// Accept header sent on token endpoint requests, e.g. "application/json"
+	Accept string
+
+	// Header holds additional headers, such as User-Agent or API keys, sent
+	// on token endpoint requests
+	Header http.Header
+
+	// ResponseParser decodes token endpoint responses, ParseResponse if nil
+	ResponseParser ResponseParser
+
+	// Realm is sent as the realm parameter of Authorization headers. It is
+	// not signed. Required by some providers, e.g. NetSuite's account ID
+	Realm string
+
+	// ExcludeQueryParams omits URL query parameters from signatures, for
+	// providers which do not sign them
+	ExcludeQueryParams bool
+
+	// ExcludeBodyParams omits form body parameters from signatures, for
+	// providers or proxies which do not sign them. Request bodies are then
+	// never read by a Client, only streamed, so arbitrarily large payloads
+	// can be sent (compressed on the fly with CompressRequests)
+	ExcludeBodyParams bool
+
+	// ExtraParams are additional protocol parameters, such as x_auth_mode,
+	// signed and sent in the Authorization header of every request
+	ExtraParams url.Values
+
+	// CompressRequests gzips the bodies of requests made with a Client
+	// after signing their uncompressed form parameters. Empty bodies are
+	// sent as they are
+	CompressRequests bool
+
+	// Routes select how requests made with a Client are signed
+	Routes []Route
+
+	// Noncer generates oauth_nonce values, a default RandomNoncer if nil
+	Noncer Noncer
+
+	// Signer computes signatures, an HMACSigner (HMAC-SHA1) if nil
+	Signer Signer
+
+	// Clock returns the time used for oauth_timestamp values, time.Now if
+	// nil
+	Clock func() time.Time
+
+	// Retry policy for failed token endpoint requests, none if nil
+	Retry *RetryPolicy
+
+	// Interceptors wrap the transport of token endpoint requests
+	Interceptors []Interceptor
+
+	// Metrics receives measurements, discarded if nil
+	Metrics Metrics
+
+	// index of the Endpoint (or one of its Fallbacks) which last succeeded
+	active int32
 }
 
 // Endpoint contains the OAuth 1.0 provider's request token,
@@ -55,68 +122,141 @@ type Endpoint struct {
 	// Authorize URL (Resource Owner Authorization URI)
 	AuthorizeURL string
 
+	// Authenticate URL, an optional variant of the Authorize URL which
+	// skips the authorization page for users who already granted access
+	AuthenticateURL string
+
 	// Access Token URL (Token Request URI)
 	AccessTokenURL string
+
+	// SignatureMethod is the oauth_signature_method the provider requires,
+	// used when the Config has no Signer. HMAC-SHA1, HMAC-SHA256 and
+	// PLAINTEXT need no further configuration; RSA methods need a Signer
+	// holding the private key.
+	SignatureMethod string
+
+	// ParamsInQuery transmits the signed protocol parameters of token
+	// endpoint and Client requests in the query rather than the
+	// Authorization header, for providers which require it, e.g. Withings.
+	// See RFC 5849 3.5.3.
+	ParamsInQuery bool
+
+	// CallbackUnconfirmed tolerates request token responses without
+	// oauth_callback_confirmed, for providers which omit it, e.g. Evernote
+	CallbackUnconfirmed bool
+
+	// EmptyTokenSecret accepts token responses with an empty
+	// oauth_token_secret, for providers which issue none, e.g. Evernote
+	EmptyTokenSecret bool
+
+	// Fallbacks are alternative endpoints (mirrors, regional hosts) tried in
+	// order when a token request fails with a connection error
+	Fallbacks []Endpoint
 }
 
 // Client returns an HTTP client using the provided access tokens.
 // HTTP transport will be obtained using the provided context.
 // The returned client and its Transport should not be modified.
 func (c *Config) Client(ctx context.Context, accessToken, accessSecret string) *http.Client {
-	return NewClient(ctx, c.ConsumerKey, c.ConsumerSecret, accessToken, accessSecret)
+	token := &Token{Token: accessToken, TokenSecret: accessSecret}
+	return c.TokenSourceClient(ctx, StaticTokenSource(token))
+}
+
+// TokenSourceClient returns an HTTP client signing each request with the
+// Token currently supplied by src.
+// HTTP transport will be obtained using the provided context.
+// The returned client and its Transport should not be modified.
+func (c *Config) TokenSourceClient(ctx context.Context, src TokenSource) *http.Client {
+	return &http.Client{Transport: c.transport(ctx, src)}
+}
+
+// Client2Legged returns an HTTP client for two-legged OAuth, signing
+// requests with the consumer credentials only: oauth_token is omitted and
+// the signing key is the consumer secret followed by an ampersand.
+// HTTP transport will be obtained using the provided context.
+// The returned client and its Transport should not be modified.
+func (c *Config) Client2Legged(ctx context.Context) *http.Client {
+	return c.TokenSourceClient(ctx, StaticTokenSource(&Token{}))
+}
+
+// LookupClient returns an HTTP client signing each request with the Token
+// lookup selects for it, for example by host, path or headers.
+// HTTP transport will be obtained using the provided context.
+// The returned client and its Transport should not be modified.
+func (c *Config) LookupClient(ctx context.Context, lookup func(*http.Request) (*Token, error)) *http.Client {
+	tr := c.transport(ctx, nil)
+	tr.TokenLookup = lookup
+	return &http.Client{Transport: tr}
+}
+
+// transport returns a Transport signing requests with the Config's consumer
+// credentials and settings and Tokens from src.
+func (c *Config) transport(ctx context.Context, src TokenSource) *Transport {
+	return &Transport{
+		Base:           internal.ContextClient(ctx).Transport,
+		consumerKey:    c.ConsumerKey,
+		consumerSecret: c.ConsumerSecret,
+		secrets:        c.SecretProvider,
+		realm:          c.Realm,
+		source:         src,
+		noncer:         c.Noncer,
+		clock:          c.Clock,
+		signer:         c.signer(),
+		excludeQuery:   c.ExcludeQueryParams,
+		excludeBody:    c.ExcludeBodyParams,
+		inQuery:        c.Endpoint.ParamsInQuery,
+		ExtraParams:    c.ExtraParams,
+		Metrics:        c.Metrics,
+		compress:       c.CompressRequests,
+		Routes:         c.Routes,
+	}
 }
 
 // RequestToken obtains a Request token and secret (temporary credential) by
 // POSTing a request (with oauth_callback in the auth header) to the Endpoint
 // RequestTokenURL. The response body form is validated to ensure
-// oauth_callback_confirmed is true. Returns the request token and secret
+// oauth_callback_confirmed is true, unless the Endpoint is
+// CallbackUnconfirmed. Returns the request token and secret
 // (temporary credentials).
 // See RFC 5849 2.1 Temporary Credentials.
 func (c *Config) RequestToken() (string, string, error) {
-	// Setup to request a request_token pair
-	req, err := http.NewRequest("POST", c.Endpoint.RequestTokenURL, nil)
-	if err != nil {
-		return "", "", err
-	}
-	params, err := prepareParams(req, c.ConsumerKey)
-	if err != nil {
-		return "", "", err
-	}
-	params.Add("oauth_callback", c.CallbackURL)
-	signer := Signer{nonce(), time.Now()}
-	signature, err := signer.Sign(c.ConsumerSecret, "", req, params)
-	if err != nil {
-		return "", "", err
-	}
-	params.Add("oauth_signature", signature)
-	req.Header.Add("Authorization", formatOAuthHeader(params))
+	return c.requestToken(c.context(), nil)
+}
 
-	// Request a request_token pair
-	res, err := internal.ContextClient(c.Context).Do(req)
-	if err != nil {
-		return "", "", err
-	}
-	defer res.Body.Close()
+// RequestTokenContext is like RequestToken but makes the request with ctx.
+func (c *Config) RequestTokenContext(ctx context.Context) (string, string, error) {
+	return c.requestToken(ctx, nil)
+}
 
-	// Handle request_token response
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return "", "", fmt.Errorf("oauth1: Server returned unexpected status %d", res.StatusCode)
-	}
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return "", "", err
+// RequestTokenWithParams is like RequestToken but adds params, such as the
+// scope or expiration some providers require, to the query of the
+// RequestTokenURL, so they are both signed and transmitted.
+func (c *Config) RequestTokenWithParams(params url.Values) (string, string, error) {
+	return c.RequestTokenWithParamsContext(c.context(), params)
+}
+
+// RequestTokenWithParamsContext is like RequestTokenWithParams but makes the
+// request with ctx.
+func (c *Config) RequestTokenWithParamsContext(ctx context.Context, params url.Values) (string, string, error) {
+	return c.requestToken(ctx, params)
+}
+
+func (c *Config) requestToken(ctx context.Context, params url.Values) (string, string, error) {
+	oauthParams := make(url.Values)
+	if c.Protocol != OAuth10 {
+		oauthParams.Add("oauth_callback", c.CallbackURL)
 	}
-	values, err := url.ParseQuery(string(body))
+	values, err := c.tokenRequest(ctx, withQuery(requestTokenURL, params), "", oauthParams, nil)
 	if err != nil {
 		return "", "", err
 	}
 	requestToken := values.Get("oauth_token")
 	requestSecret := values.Get("oauth_token_secret")
-	if requestToken == "" || requestSecret == "" {
-		return "", "", errors.New("oauth1: Response missing oauth_token or oauth_token_secret")
+	if requestToken == "" || requestSecret == "" && !c.Endpoint.EmptyTokenSecret {
+		return "", "", ErrMissingToken
 	}
-	if values.Get("oauth_callback_confirmed") != "true" {
-		return "", "", errors.New("oauth1: oauth_callback_confirmed was not true")
+	if c.Protocol != OAuth10 && !c.Endpoint.CallbackUnconfirmed && values.Get("oauth_callback_confirmed") != "true" {
+		return "", "", ErrCallbackNotConfirmed
 	}
 	return requestToken, requestSecret, nil
 }
@@ -126,12 +266,46 @@ func (c *Config) RequestToken() (string, string, error) {
 // authorize the consumer to act on his/her/its behalf.
 // See RFC 5849 2.2 Resource Owner Authorization.
 func (c *Config) AuthorizationURL(requestToken string) (*url.URL, error) {
-	authorizationURL, err := url.Parse(c.Endpoint.AuthorizeURL)
+	return c.AuthorizationURLWithParams(requestToken, nil)
+}
+
+// AuthorizationURLWithParams is like AuthorizationURL but adds params, such
+// as Twitter's force_login or Flickr's perms, to the query of the URL.
+func (c *Config) AuthorizationURLWithParams(requestToken string, params url.Values) (*url.URL, error) {
+	return c.userURL(c.activeEndpoint().AuthorizeURL, requestToken, params)
+}
+
+// AuthenticationURL is like AuthorizationURL but returns the URL to the
+// Endpoint's AuthenticateURL, such as Sign in with Twitter's, which
+// redirects users who have already authorized the consumer straight to the
+// callback. Falls back to the AuthorizeURL if the Endpoint has no
+// AuthenticateURL.
+func (c *Config) AuthenticationURL(requestToken string) (*url.URL, error) {
+	endpoint := c.activeEndpoint()
+	if endpoint.AuthenticateURL == "" {
+		return c.userURL(endpoint.AuthorizeURL, requestToken, nil)
+	}
+	return c.userURL(endpoint.AuthenticateURL, requestToken, nil)
+}
+
+// userURL returns rawurl, a page of the provider the user is sent to, with
+// params and the request token added to its query, along with the callback
+// URL under OAuth10.
+func (c *Config) userURL(rawurl, requestToken string, params url.Values) (*url.URL, error) {
+	authorizationURL, err := url.Parse(rawurl)
 	if err != nil {
 		return nil, err
 	}
 	values := authorizationURL.Query()
+	for key, vs := range params {
+		for i := range vs {
+			values.Add(key, vs[i])
+		}
+	}
 	values.Add("oauth_token", requestToken)
+	if c.Protocol == OAuth10 && c.CallbackURL != "" {
+		values.Add("oauth_callback", c.CallbackURL)
+	}
 	authorizationURL.RawQuery = values.Encode()
 	return authorizationURL, nil
 }
@@ -149,7 +323,7 @@ func ParseAuthorizationCallback(req *http.Request) (string, string, error) {
 	requestToken := req.Form.Get("oauth_token")
 	verifier := req.Form.Get("oauth_verifier")
 	if requestToken == "" || verifier == "" {
-		return "", "", errors.New("oauth1: Request missing oauth_token or oauth_verifier")
+		return "", "", ErrMissingVerifier
 	}
 	return requestToken, verifier, nil
 }
@@ -160,50 +334,125 @@ func ParseAuthorizationCallback(req *http.Request) (string, string, error) {
 // credentials).
 // See RFC 5849 2.3 Token Credentials.
 func (c *Config) AccessToken(requestToken, requestSecret, verifier string) (string, string, error) {
-	// Setup to request an access_token pair
-	req, err := http.NewRequest("POST", c.Endpoint.AccessTokenURL, nil)
+	return c.AccessTokenContext(c.context(), requestToken, requestSecret, verifier)
+}
+
+// AccessTokenContext is like AccessToken but makes the request with ctx.
+func (c *Config) AccessTokenContext(ctx context.Context, requestToken, requestSecret, verifier string) (string, string, error) {
+	token, _, err := c.accessToken(ctx, requestToken, requestSecret, verifier)
 	if err != nil {
 		return "", "", err
 	}
-	params, err := prepareParams(req, c.ConsumerKey)
+	return token.Token, token.TokenSecret, nil
+}
+
+// AccessTokenResponse is like AccessToken but returns the access token as a
+// *Token along with all parameters of the token endpoint response, such as
+// Twitter's user_id and screen_name.
+func (c *Config) AccessTokenResponse(requestToken, requestSecret, verifier string) (*Token, url.Values, error) {
+	return c.AccessTokenResponseContext(c.context(), requestToken, requestSecret, verifier)
+}
+
+// AccessTokenResponseContext is like AccessTokenResponse but makes the
+// request with ctx.
+func (c *Config) AccessTokenResponseContext(ctx context.Context, requestToken, requestSecret, verifier string) (*Token, url.Values, error) {
+	return c.accessToken(ctx, requestToken, requestSecret, verifier)
+}
+
+func (c *Config) accessToken(ctx context.Context, requestToken, requestSecret, verifier string) (*Token, url.Values, error) {
+	oauthParams := make(url.Values)
+	oauthParams.Add("oauth_token", requestToken)
+	if c.Protocol != OAuth10 {
+		oauthParams.Add("oauth_verifier", verifier)
+	}
+	values, err := c.tokenRequest(ctx, accessTokenURL, requestSecret, oauthParams, nil)
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
 	}
-	params.Add("oauth_token", requestToken)
-	params.Add("oauth_verifier", verifier)
-	signer := Signer{nonce(), time.Now()}
-	signature, err := signer.Sign(c.ConsumerSecret, "", req, params)
+	token, err := c.tokenFromValues(values)
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
 	}
-	params.Add("oauth_signature", signature)
-	req.Header.Add("Authorization", formatOAuthHeader(params))
+	return token, values, nil
+}
 
-	// Request an access_token pair
-	res, err := internal.ContextClient(c.Context).Do(req)
+// tokenRequest POSTs a request carrying oauthParams, and form as its body if
+// non-nil, signed with tokenSecret to the token endpoint URL selected by
+// endpointURL with ctx and returns the response body decoded by the
+// ResponseParser.
+// Connection errors fail over to the Endpoint's Fallbacks and failed
+// attempts are retried, with a fresh signature, according to the Retry
+// policy.
+func (c *Config) tokenRequest(ctx context.Context, endpointURL func(Endpoint) string, tokenSecret string, oauthParams, form url.Values) (values url.Values, err error) {
+	defer func(start time.Time) {
+		c.metrics().TokenRequest(endpointURL(c.activeEndpoint()), time.Since(start), err)
+	}(time.Now())
+	build := func(e Endpoint) (*http.Request, error) {
+		var body io.Reader
+		if form != nil {
+			body = strings.NewReader(form.Encode())
+		}
+		req, err := http.NewRequest("POST", endpointURL(e), body)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		for key, values := range c.Header {
+			req.Header[key] = append([]string(nil), values...)
+		}
+		st := stamp{newNonce(c.Noncer), signingTime(c.Clock)}
+		if err := c.signing().authorize(req, tokenSecret, oauthParams, st); err != nil {
+			return nil, err
+		}
+		if c.Accept != "" {
+			req.Header.Set("Accept", c.Accept)
+		}
+		return req, nil
+	}
+	res, err := c.Retry.do(ctx, func() (*http.Response, error) {
+		return c.failover(func(e Endpoint) (*http.Request, error) {
+			req, err := build(e)
+			if err != nil {
+				return nil, permanentError{err}
+			}
+			return req, nil
+		}, c.tokenClient().Do)
+	})
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer res.Body.Close()
+	recordClockSkew(res)
 
-	// Handle access_token response
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return "", "", fmt.Errorf("oauth1: Server returned unexpected status %d", res.StatusCode)
-	}
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	values, err := url.ParseQuery(string(body))
-	if err != nil {
-		return "", "", err
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		tokenErr := &TokenRequestError{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       body,
+			Problem:    parseProblem(res.Header, body),
+		}
+		if res.Request != nil {
+			tokenErr.Endpoint = res.Request.URL.String()
+		}
+		return nil, tokenErr
 	}
-	accessToken := values.Get("oauth_token")
-	accessSecret := values.Get("oauth_token_secret")
-	if accessToken == "" || accessSecret == "" {
-		return "", "", errors.New("oauth1: Response missing oauth_token or oauth_token_secret")
+	return c.responseParser()(responseMediaType(res, c.Accept), body)
+}
+
+// context returns the Config's Context for requests made without an explicit
+// one, context.Background() if nil.
+func (c *Config) context() context.Context {
+	if c.Context != nil {
+		return c.Context
 	}
-	return accessToken, accessSecret, nil
+	return context.Background()
 }
 
 // HTTPClient is the context key to use with 's WithValue function
@@ -217,59 +466,226 @@ var HTTPClient internal.ContextKey
 // is used only for token acquisition and is not used to configure the
 // *http.Client returned from NewClient.
 func NewClient(ctx context.Context, consumerKey, consumerSecret, accessToken, accessSecret string) *http.Client {
-	return &http.Client{
-		Transport: &Transport{
-			Base:           internal.ContextClient(ctx).Transport,
-			consumerKey:    consumerKey,
-			consumerSecret: consumerSecret,
-			accessToken:    accessToken,
-			accessSecret:   accessSecret,
-		},
-	}
-}
-
-// Signer provdes dyanmic data required to sign an OAuth1 signature.
-type Signer struct {
-	Nonce     string
-	Timestamp time.Time
-}
-
-// Base returns the signature base string
-func (s Signer) Base(req *http.Request, params url.Values) string {
-	params.Add("oauth_nonce", s.Nonce)
-	params.Add("oauth_timestamp", strconv.FormatInt(s.Timestamp.Unix(), 10))
-	baseURL, _ := url.Parse(req.URL.String())
-	baseURL.RawQuery = ""
-	upperMethod := strings.ToUpper(req.Method)
-	escapedURL := url.QueryEscape(baseURL.String())
-	escapedParams := url.QueryEscape(normalizeSpace(params.Encode()))
-	return strings.Join([]string{upperMethod, escapedURL, escapedParams}, "&")
-}
-
-// Sign creates a concatenated consumer and token secret key and calculates
-// the HMAC digest of the message. Returns the base64 encoded digest bytes.
-func (s Signer) Sign(consumerSecret, tokenSecret string, req *http.Request, params url.Values) (string, error) {
-	base := s.Base(req, params)
-	key := strings.Join([]string{consumerSecret, tokenSecret}, "&")
-	h := hmac.New(sha1.New, []byte(key))
-	if _, err := h.Write([]byte(base)); err != nil {
+	config := &Config{ConsumerKey: consumerKey, ConsumerSecret: consumerSecret}
+	return config.Client(ctx, accessToken, accessSecret)
+}
+
+// stamp holds the nonce and timestamp a signature is made with.
+type stamp struct {
+	nonce     string
+	timestamp time.Time
+}
+
+// base returns the signature base string of req and params, to which the
+// nonce and timestamp are added.
+func (s stamp) base(req *http.Request, params url.Values) string {
+	params.Add("oauth_nonce", s.nonce)
+	params.Add("oauth_timestamp", strconv.FormatInt(s.timestamp.Unix(), 10))
+	return signatureBase(signatureHTTPMethod(req), req.URL, params)
+}
+
+// signatureBase returns the signature base string of a request with the
+// uppercased HTTP method to u carrying params. See RFC 5849 3.4.1.
+func signatureBase(method string, u *url.URL, params url.Values) string {
+	escapedURL := percentEncode(baseStringURI(u))
+	escapedParams := percentEncode(encodeParams(params))
+	return strings.Join([]string{method, escapedURL, escapedParams}, "&")
+}
+
+// baseStringURI returns u normalized for the signature base string per RFC
+// 5849 3.4.1.2: the scheme and host are lowercased, the default port of the
+// scheme is removed and the path is kept as sent, without query or fragment,
+// except for percent encoded octets (such as UTF-8 characters) which are
+// uppercased.
+func baseStringURI(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if scheme == "http" && strings.HasSuffix(host, ":80") || scheme == "https" && strings.HasSuffix(host, ":443") {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+	path := upperPercentEncoding(u.EscapedPath())
+	if path == "" {
+		path = "/"
+	}
+	return scheme + "://" + host + path
+}
+
+// signing holds the consumer credentials and options requests are signed
+// with.
+type signing struct {
+	consumerKey    string
+	consumerSecret string
+
+	// secrets supplies the consumer secret in place of consumerSecret if
+	// non-nil
+	secrets SecretProvider
+
+	// realm is emitted first in the Authorization header, unsigned
+	realm string
+
+	// excludeQuery omits URL query parameters from the signature
+	excludeQuery bool
+
+	// excludeBody omits form body parameters from the signature
+	excludeBody bool
+
+	// extraParams are added to the signed parameters and header
+	extraParams url.Values
+
+	// inQuery transmits the protocol parameters in the query rather than
+	// the Authorization header
+	inQuery bool
+
+	// signer computes signatures, an HMACSigner if nil
+	signer Signer
+}
+
+func (c *Config) signing() signing {
+	return signing{
+		consumerKey:    c.ConsumerKey,
+		consumerSecret: c.ConsumerSecret,
+		secrets:        c.SecretProvider,
+		realm:          c.Realm,
+		excludeQuery:   c.ExcludeQueryParams,
+		excludeBody:    c.ExcludeBodyParams,
+		extraParams:    c.ExtraParams,
+		inQuery:        c.Endpoint.ParamsInQuery,
+		signer:         c.signer(),
+	}
+}
+
+// method returns the Signer of s, an HMACSigner if none is set.
+func (s signing) method() Signer {
+	if s.signer == nil {
+		return HMACSigner{}
+	}
+	return s.signer
+}
+
+// sign returns the signature of req and params, to which the nonce and
+// timestamp of st are added, keyed with the percent encoded consumer and
+// token secrets.
+func (s signing) sign(req *http.Request, tokenSecret string, params url.Values, st stamp) (string, error) {
+	consumerSecret := s.consumerSecret
+	if s.secrets != nil {
+		var err error
+		if consumerSecret, err = s.secrets.ConsumerSecret(); err != nil {
+			return "", err
+		}
+	}
+	key := strings.Join([]string{percentEncode(consumerSecret), percentEncode(tokenSecret)}, "&")
+	return s.method().Sign(key, st.base(req, params))
+}
+
+// authorizationHeader signs req and returns the value of its OAuth
+// Authorization header. oauthParams are protocol parameters, such as
+// oauth_token, added to the request parameters and those of st.
+func (s signing) authorizationHeader(req *http.Request, tokenSecret string, oauthParams url.Values, st stamp) (string, error) {
+	params, err := s.signedParams(req, tokenSecret, oauthParams, st)
+	if err != nil {
 		return "", err
 	}
-	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+	return s.header(params), nil
+}
+
+// authorize signs req like authorizationHeader and transmits its protocol
+// parameters in the Authorization header, or appended to the query of its
+// URL if s is inQuery. See RFC 5849 3.5.
+func (s signing) authorize(req *http.Request, tokenSecret string, oauthParams url.Values, st stamp) error {
+	if !s.inQuery {
+		header, err := s.authorizationHeader(req, tokenSecret, oauthParams, st)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", header)
+		return nil
+	}
+	params, err := s.signedParams(req, tokenSecret, oauthParams, st)
+	if err != nil {
+		return err
+	}
+	// copy the URL, which a cloned request shares with the original
+	u := *req.URL
+	if u.RawQuery != "" {
+		u.RawQuery += "&"
+	}
+	u.RawQuery += encodeParams(s.protocolParams(params))
+	req.URL = &u
+	return nil
+}
+
+// signedParams returns the parameters of req and oauthParams, along with
+// those of st and the oauth_signature.
+func (s signing) signedParams(req *http.Request, tokenSecret string, oauthParams url.Values, st stamp) (params url.Values, err error) {
+	defer func() { recordSignature(err) }()
+	params, err = s.params(req)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range oauthParams {
+		for i := range values {
+			params.Add(key, values[i])
+		}
+	}
+	signature, err := s.sign(req, tokenSecret, params, st)
+	if err != nil {
+		return nil, err
+	}
+	params.Add("oauth_signature", signature)
+	return params, nil
+}
+
+// header formats the Authorization header carrying the protocol parameters
+// among the signed params, preceded by the realm if any. See RFC 5849
+// 3.5.1.
+func (s signing) header(params url.Values) string {
+	return FormatOAuthHeader(s.realm, s.protocolParams(params))
 }
 
-func nonce() string {
-	h := md5.New()
-	now := time.Now().Unix()
-	io.WriteString(h, strconv.FormatInt(now, 10))
-	io.WriteString(h, strconv.FormatInt(rand.Int63(), 10))
-	return fmt.Sprintf("%x", h.Sum(nil))
+// protocolParams returns the oauth_* and extra parameters among the signed
+// params. Request parameters from the query or body are signed only.
+func (s signing) protocolParams(params url.Values) url.Values {
+	protocolParams := make(url.Values)
+	for key, values := range params {
+		if _, extra := s.extraParams[key]; extra || strings.HasPrefix(key, "oauth_") {
+			protocolParams[key] = values
+		}
+	}
+	return protocolParams
 }
 
 func prepareParams(r *http.Request, consumerKey string) (url.Values, error) {
+	return signing{consumerKey: consumerKey}.params(r)
+}
+
+// params collects the decoded form body and query parameters of r along with
+// the extra, consumer key, signature method and version protocol
+// parameters. Parameters are encoded only when the base string and header
+// are formatted.
+func (s signing) params(r *http.Request) (url.Values, error) {
+	params, err := s.requestParams(r)
+	if err != nil {
+		return params, err
+	}
+	for key, values := range s.extraParams {
+		for i := range values {
+			params.Add(key, values[i])
+		}
+	}
+	params.Add("oauth_consumer_key", s.consumerKey)
+	params.Add("oauth_signature_method", s.method().Name())
+	params.Add("oauth_version", "1.0")
+	return params, nil
+}
+
+// requestParams collects the decoded form body and query parameters of r,
+// unless excluded. A form body is read and replaced with an equivalent
+// reader.
+func (s signing) requestParams(r *http.Request) (url.Values, error) {
 	params := make(url.Values)
-	if r.Body != nil && r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+	if !s.excludeBody && r.Body != nil && isFormBody(r) {
 		b, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
 		if err != nil {
 			return params, err
 		}
@@ -278,28 +694,24 @@ func prepareParams(r *http.Request, consumerKey string) (url.Values, error) {
 			return params, err
 		}
 		r.Body = ioutil.NopCloser(bytes.NewReader(b))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(b)), nil
+		}
 	}
-	for key, values := range r.URL.Query() {
-		for i := range values {
-			params.Add(key, url.QueryEscape(values[i]))
+	if !s.excludeQuery {
+		for key, values := range r.URL.Query() {
+			for i := range values {
+				params.Add(key, values[i])
+			}
 		}
 	}
-	params.Add("oauth_consumer_key", consumerKey)
-	params.Add("oauth_signature_method", "HMAC-SHA1")
-	params.Add("oauth_version", "1.0")
 	return params, nil
 }
 
-func formatOAuthHeader(params url.Values) string {
-	joined := normalizeSpace(params.Encode())
-	pairs := strings.Split(joined, "&")
-	for i := range pairs {
-		pair := strings.Split(pairs[i], "=")
-		pairs[i] = fmt.Sprintf("%s=\"%s\"", pair[0], pair[1])
-	}
-	return fmt.Sprintf("OAuth %s", strings.Join(pairs, ", "))
-}
-
-func normalizeSpace(s string) string {
-	return strings.Replace(s, "+", "%20", -1)
+// isFormBody reports whether r has a form body whose parameters are signed.
+// Other bodies, notably multipart/form-data uploads, are not signed. See
+// RFC 5849 3.4.1.3.1.
+func isFormBody(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
 }
@@ -1,11 +1,20 @@
 package oauth1
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -128,6 +137,58 @@ func TestConfigRequestToken_InvalidRequestTokenURL(t *testing.T) {
 	assert.Equal(t, "", requestSecret)
 }
 
+func TestConfigRequestTokenContext_Canceled(t *testing.T) {
+	data := url.Values{"oauth_token": {"request_token"}, "oauth_token_secret": {"request_secret"}}
+	server := newRequestTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL,
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := config.RequestTokenContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestConfigAccessTokenContext(t *testing.T) {
+	data := url.Values{"oauth_token": {"access_token"}, "oauth_token_secret": {"access_secret"}}
+	server := newAccessTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			AccessTokenURL: server.URL,
+		},
+	}
+	accessToken, accessSecret, err := config.AccessTokenContext(context.Background(), "request_token", "request_secret", expectedVerifier)
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", accessToken)
+	assert.Equal(t, "access_secret", accessSecret)
+}
+
+func TestConfigAccessToken_SignedWithRequestSecret(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "consumer_secret%26request_secret", params["oauth_signature"])
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=access_token&oauth_token_secret=access_secret"))
+	})
+	defer server.Close()
+
+	config := &Config{
+		ConsumerSecret: "consumer_secret",
+		Signer:         PlaintextSigner{},
+		Endpoint: Endpoint{
+			AccessTokenURL: server.URL,
+		},
+	}
+	_, _, err := config.AccessToken("request_token", "request_secret", expectedVerifier)
+	assert.Nil(t, err)
+}
+
 func TestConfigAccessToken_CannotParseBody(t *testing.T) {
 	server := newUnparseableBodyServer()
 	defer server.Close()
@@ -229,3 +290,286 @@ func TestParseAuthorizationCallback_MissingTokenOrVerifier(t *testing.T) {
 	url.RawQuery = query.Encode()
 	http.Get(url.String())
 }
+
+func TestSigning_ExcludeQuery(t *testing.T) {
+	st := stamp{"nonce", time.Unix(1318467427, 0)}
+	s := signing{consumerKey: "consumer_key", consumerSecret: "consumer_secret", excludeQuery: true}
+
+	withQuery, err := http.NewRequest("GET", "https://api.example.com/1/feed?count=2", nil)
+	assert.Nil(t, err)
+	header, err := s.authorizationHeader(withQuery, "token_secret", nil, st)
+	assert.Nil(t, err)
+	params := parseOAuthParamsOrFail(t, header)
+	assert.NotContains(t, params, "count")
+
+	withoutQuery, err := http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	s.excludeQuery = false
+	expected, err := s.authorizationHeader(withoutQuery, "token_secret", nil, stamp{"nonce", time.Unix(1318467427, 0)})
+	assert.Nil(t, err)
+	assert.Equal(t, parseOAuthParamsOrFail(t, expected)["oauth_signature"], params["oauth_signature"])
+}
+
+func TestConfigClient_ExcludeQueryParams(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.NotContains(t, params, "count")
+		assert.Equal(t, "2", req.URL.Query().Get("count"))
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ExcludeQueryParams: true}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL + "?count=2")
+	assert.Nil(t, err)
+}
+
+func TestSigning_Realm(t *testing.T) {
+	s := signing{consumerKey: "consumer_key", consumerSecret: "consumer_secret"}
+	req, err := http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	expected, err := s.authorizationHeader(req, "token_secret", nil, stamp{"nonce", time.Unix(1318467427, 0)})
+	assert.Nil(t, err)
+
+	s.realm = "1234567"
+	header, err := s.authorizationHeader(req, "token_secret", nil, stamp{"nonce", time.Unix(1318467427, 0)})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(header, `OAuth realm="1234567", `))
+	params := parseOAuthParamsOrFail(t, header)
+	assert.Equal(t, parseOAuthParamsOrFail(t, expected)["oauth_signature"], params["oauth_signature"])
+}
+
+func TestConfigClient_Realm(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "ACCOUNTID", params["realm"])
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", Realm: "ACCOUNTID"}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestSigning_ExcludeBody(t *testing.T) {
+	s := signing{consumerKey: "consumer_key", consumerSecret: "consumer_secret", excludeBody: true}
+	req, err := http.NewRequest("POST", "https://api.example.com/1/statuses", strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body := req.Body
+	params, err := s.params(req)
+	assert.Nil(t, err)
+	assert.NotContains(t, params, "status")
+	assert.True(t, body == req.Body)
+}
+
+func TestConfigClient_ExcludeBodyParams(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.NotContains(t, params, "status")
+		assert.Equal(t, "hello", req.PostFormValue("status"))
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ExcludeBodyParams: true}
+	_, err := config.Client(NoContext, "token", "secret").PostForm(server.URL, url.Values{"status": {"hello"}})
+	assert.Nil(t, err)
+}
+
+func TestSigning_FormBodyWithCharset(t *testing.T) {
+	s := signing{consumerKey: "consumer_key"}
+	req, err := http.NewRequest("POST", "https://api.example.com/1/statuses", strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	params, err := s.params(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", params.Get("status"))
+}
+
+func TestSigning_MultipartBody(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	assert.Nil(t, w.WriteField("status", "hello"))
+	part, err := w.CreateFormFile("media", "image.png")
+	assert.Nil(t, err)
+	part.Write([]byte("\x89PNG"))
+	assert.Nil(t, w.Close())
+
+	s := signing{consumerKey: "consumer_key"}
+	req, err := http.NewRequest("POST", "https://upload.example.com/1/media/upload.json", &body)
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	reqBody := req.Body
+	params, err := s.params(req)
+	assert.Nil(t, err)
+	assert.NotContains(t, params, "status")
+	assert.True(t, reqBody == req.Body)
+}
+
+func TestConfigClient_ExtraParams(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "client_auth", params["x_auth_mode"])
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ExtraParams: url.Values{"x_auth_mode": {"client_auth"}}}
+	_, err := config.Client(NoContext, "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestSigning_ExtraParamsSigned(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	s := signing{consumerKey: "consumer_key", consumerSecret: "consumer_secret"}
+	without, err := s.authorizationHeader(req, "token_secret", nil, stamp{"nonce", time.Unix(1318467427, 0)})
+	assert.Nil(t, err)
+	s.extraParams = url.Values{"x_auth_mode": {"client_auth"}}
+	with, err := s.authorizationHeader(req, "token_secret", nil, stamp{"nonce", time.Unix(1318467427, 0)})
+	assert.Nil(t, err)
+	assert.NotEqual(t, parseOAuthParamsOrFail(t, without)["oauth_signature"], parseOAuthParamsOrFail(t, with)["oauth_signature"])
+}
+
+func TestConfigClient2Legged(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.NotContains(t, params, "oauth_token")
+		assert.Equal(t, "consumer_key", params["oauth_consumer_key"])
+
+		timestamp, err := strconv.ParseInt(params["oauth_timestamp"], 10, 64)
+		assert.Nil(t, err)
+		signed := url.Values{
+			"oauth_consumer_key":     {"consumer_key"},
+			"oauth_signature_method": {"HMAC-SHA1"},
+			"oauth_version":          {"1.0"},
+		}
+		req.URL.Host = req.Host
+		req.URL.Scheme = "http"
+		base := stamp{params["oauth_nonce"], time.Unix(timestamp, 0)}.base(req, signed)
+		mac := hmac.New(sha1.New, []byte("consumer_secret&"))
+		mac.Write([]byte(base))
+		expected := url.QueryEscape(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+		assert.Equal(t, expected, params["oauth_signature"])
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	_, err := config.Client2Legged(NoContext).Get(server.URL + "/resource")
+	assert.Nil(t, err)
+}
+
+func TestConfigAccessTokenResponse(t *testing.T) {
+	data := url.Values{}
+	data.Add("oauth_token", "access_token")
+	data.Add("oauth_token_secret", "access_secret")
+	data.Add("user_id", "6253282")
+	data.Add("screen_name", "twitterapi")
+	server := newAccessTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			AccessTokenURL: server.URL,
+		},
+	}
+	token, values, err := config.AccessTokenResponse("request_token", "request_secret", expectedVerifier)
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", token.Token)
+	assert.Equal(t, "access_secret", token.TokenSecret)
+	assert.Equal(t, "6253282", values.Get("user_id"))
+	assert.Equal(t, "twitterapi", values.Get("screen_name"))
+}
+
+func TestConfigRequestTokenWithParams(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "email_r listings_r", req.URL.Query().Get("scope"))
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.NotContains(t, params, "scope")
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"))
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL + "/oauth/request_token",
+		},
+	}
+	requestToken, requestSecret, err := config.RequestTokenWithParams(url.Values{"scope": {"email_r listings_r"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+	assert.Equal(t, "request_secret", requestSecret)
+}
+
+func TestConfigAuthorizationURL(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{
+			AuthorizeURL: "https://api.twitter.com/oauth/authorize",
+		},
+	}
+	authorizationURL, err := config.AuthorizationURL("request_token")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.twitter.com/oauth/authorize?oauth_token=request_token", authorizationURL.String())
+}
+
+func TestConfigAuthorizationURLWithParams(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{
+			AuthorizeURL: "https://www.flickr.com/services/oauth/authorize?lang=en",
+		},
+	}
+	authorizationURL, err := config.AuthorizationURLWithParams("request_token", url.Values{"perms": {"write"}})
+	assert.Nil(t, err)
+	query := authorizationURL.Query()
+	assert.Equal(t, "en", query.Get("lang"))
+	assert.Equal(t, "write", query.Get("perms"))
+	assert.Equal(t, "request_token", query.Get("oauth_token"))
+}
+
+func TestConfigAuthorizationURL_Invalid(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{
+			AuthorizeURL: "%gh&%ij",
+		},
+	}
+	_, err := config.AuthorizationURLWithParams("request_token", url.Values{"force_login": {"true"}})
+	assert.Error(t, err)
+}
+
+func TestConfigAuthenticationURL(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{
+			AuthorizeURL:    "https://api.twitter.com/oauth/authorize",
+			AuthenticateURL: "https://api.twitter.com/oauth/authenticate",
+		},
+	}
+	authenticationURL, err := config.AuthenticationURL("request_token")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.twitter.com/oauth/authenticate?oauth_token=request_token", authenticationURL.String())
+
+	config.Endpoint.AuthenticateURL = ""
+	authenticationURL, err = config.AuthenticationURL("request_token")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.twitter.com/oauth/authorize?oauth_token=request_token", authenticationURL.String())
+}
+
+func TestConfigRequestToken_Header(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "myapp/1.0", req.Header.Get("User-Agent"))
+		assert.Equal(t, "api_key", req.Header.Get("X-Api-Key"))
+		assert.NotEmpty(t, parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))["oauth_signature"])
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"))
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: server.URL},
+		Header: http.Header{
+			"User-Agent":    {"myapp/1.0"},
+			"X-Api-Key":     {"api_key"},
+			"Authorization": {"Bearer ignored"},
+		},
+	}
+	_, _, err := config.RequestToken()
+	assert.Nil(t, err)
+}
@@ -0,0 +1,13 @@
+module github.com/ktnyt/oauth1/oauth1otel
+
+go 1.25.0
+
+require (
+	github.com/ktnyt/oauth1 v0.0.0
+	github.com/stretchr/testify v1.12.1
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/sdk v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+replace github.com/ktnyt/oauth1 => ../
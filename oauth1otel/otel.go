@@ -0,0 +1,126 @@
+// Package oauth1otel instruments OAuth1 clients with OpenTelemetry tracing.
+//
+// Spans record the provider host, HTTP method and response status only;
+// credentials, signatures and other request contents are never recorded.
+package oauth1otel
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ktnyt/oauth1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer of this package.
+const instrumentationName = "github.com/ktnyt/oauth1/oauth1otel"
+
+// Span names.
+const (
+	RequestTokenSpan = "oauth1.RequestToken"
+	AccessTokenSpan  = "oauth1.AccessToken"
+	RoundTripSpan    = "oauth1.RoundTrip"
+)
+
+// An Option configures instrumentation.
+type Option func(*options)
+
+type options struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the TracerProvider spans are created with, the
+// global one by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.provider = provider
+	}
+}
+
+func newTracer(opts []Option) trace.Tracer {
+	o := options{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.provider.Tracer(instrumentationName)
+}
+
+// Instrument adds an Interceptor to config creating a span for each token
+// endpoint request made by RequestToken, AccessToken and related calls.
+// Requests to the request token URL are traced as RequestTokenSpan, all
+// others as AccessTokenSpan.
+func Instrument(config *oauth1.Config, opts ...Option) {
+	tracer := newTracer(opts)
+	interceptor := func(next http.RoundTripper) http.RoundTripper {
+		return oauth1.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			name := AccessTokenSpan
+			if isRequestTokenURL(config.Endpoint, req) {
+				name = RequestTokenSpan
+			}
+			return roundTrip(tracer, name, next, req)
+		})
+	}
+	config.Interceptors = append([]oauth1.Interceptor{interceptor}, config.Interceptors...)
+}
+
+// isRequestTokenURL reports whether req is sent to the request token URL
+// of endpoint or one of its Fallbacks.
+func isRequestTokenURL(endpoint oauth1.Endpoint, req *http.Request) bool {
+	if withoutQuery(endpoint.RequestTokenURL) == withoutQuery(req.URL.String()) {
+		return true
+	}
+	for _, fallback := range endpoint.Fallbacks {
+		if isRequestTokenURL(fallback, req) {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutQuery(rawurl string) string {
+	if i := strings.IndexByte(rawurl, '?'); i >= 0 {
+		return rawurl[:i]
+	}
+	return rawurl
+}
+
+// Client returns a copy of client, typically one returned by Config.Client,
+// creating a span for each signed request it sends.
+func Client(client *http.Client, opts ...Option) *http.Client {
+	tracer := newTracer(opts)
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	instrumented := *client
+	instrumented.Transport = oauth1.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return roundTrip(tracer, RoundTripSpan, next, req)
+	})
+	return &instrumented
+}
+
+// roundTrip sends req with next within a span of the given name.
+func roundTrip(tracer trace.Tracer, name string, next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	ctx, span := tracer.Start(req.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+	res, err := next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	if res.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+	}
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package oauth1otel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ktnyt/oauth1"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder() (*tracetest.SpanRecorder, Option) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return recorder, WithTracerProvider(provider)
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestInstrument(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/request_token", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"))
+	})
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	recorder, opt := newRecorder()
+	config := &oauth1.Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		Endpoint: oauth1.Endpoint{
+			RequestTokenURL: server.URL + "/oauth/request_token",
+			AccessTokenURL:  server.URL + "/oauth/access_token",
+		},
+	}
+	Instrument(config, opt)
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.Nil(t, err)
+	_, _, err = config.AccessToken(requestToken, requestSecret, "verifier")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, RequestTokenSpan, spans[0].Name())
+		assert.Equal(t, int64(200), attributes(spans[0])["http.response.status_code"].AsInt64())
+		assert.Equal(t, "127.0.0.1", attributes(spans[0])["server.address"].AsString())
+		assert.Equal(t, AccessTokenSpan, spans[1].Name())
+		assert.Equal(t, int64(401), attributes(spans[1])["http.response.status_code"].AsInt64())
+	}
+	for _, span := range spans {
+		for _, kv := range span.Attributes() {
+			assert.False(t, strings.Contains(kv.Value.Emit(), "secret"), "span records %s", kv.Key)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "OAuth "))
+	}))
+	defer server.Close()
+
+	recorder, opt := newRecorder()
+	config := &oauth1.Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	client := Client(config.Client(oauth1.NoContext, "token", "token_secret"), opt)
+	res, err := client.Get(server.URL + "/1.1/statuses/home_timeline.json")
+	assert.Nil(t, err)
+	res.Body.Close()
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, RoundTripSpan, spans[0].Name())
+		assert.Equal(t, "GET", attributes(spans[0])["http.request.method"].AsString())
+		assert.Equal(t, int64(200), attributes(spans[0])["http.response.status_code"].AsInt64())
+	}
+}
@@ -0,0 +1,85 @@
+package oauth1test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+)
+
+// maxRedirects is the number of redirects a Browser follows per visit.
+const maxRedirects = 10
+
+// Browser is an in-memory browser stub which follows redirects and keeps
+// cookies. Requests to the Provider are sent to its server while all other
+// requests are served in memory by App, so application login and callback
+// handlers can be tested without starting a server.
+type Browser struct {
+	Provider *Provider
+
+	// App serves every request not addressed to the Provider
+	App http.Handler
+
+	jar http.CookieJar
+}
+
+// Visit GETs rawurl, following redirects, and returns the final response.
+func (b *Browser) Visit(rawurl string) (*http.Response, error) {
+	if b.jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		b.jar = jar
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i <= maxRedirects; i++ {
+		res, err := b.get(u)
+		if err != nil {
+			return nil, err
+		}
+		b.jar.SetCookies(u, res.Cookies())
+		switch res.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+			http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return res, nil
+		}
+		location, err := res.Location()
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		u = location
+	}
+	return nil, fmt.Errorf("oauth1test: stopped after %d redirects", maxRedirects)
+}
+
+func (b *Browser) get(u *url.URL) (*http.Response, error) {
+	if b.Provider != nil && "http://"+u.Host == b.Provider.Server.URL {
+		req, err := http.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		b.addCookies(req)
+		return noRedirectClient.Do(req)
+	}
+	if b.App == nil {
+		return nil, fmt.Errorf("oauth1test: no App to serve %s", u)
+	}
+	req := httptest.NewRequest("GET", u.String(), nil)
+	b.addCookies(req)
+	rec := httptest.NewRecorder()
+	b.App.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func (b *Browser) addCookies(req *http.Request) {
+	for _, cookie := range b.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+}
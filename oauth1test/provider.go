@@ -0,0 +1,233 @@
+// Package oauth1test provides a fake OAuth1 provider and an in-memory browser
+// for testing code that runs the three-legged authorization flow.
+package oauth1test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// Provider is a fake OAuth1 provider backed by an httptest.Server. It
+// verifies the signatures of token requests like a real provider, issues
+// deterministic credentials and grants every authorization request
+// immediately, as if the user had approved access.
+type Provider struct {
+	Server *httptest.Server
+
+	// Consumer credentials the provider accepts
+	ConsumerKey    string
+	ConsumerSecret string
+
+	mu            sync.Mutex
+	issued        int
+	requestTokens map[string]*pendingToken
+	accessToken   string
+	accessSecret  string
+}
+
+type pendingToken struct {
+	secret   string
+	callback string
+	verifier string
+}
+
+// NewProvider starts a fake provider which accepts the given consumer
+// credentials. Callers should Close it when finished.
+func NewProvider(consumerKey, consumerSecret string) *Provider {
+	p := &Provider{
+		ConsumerKey:    consumerKey,
+		ConsumerSecret: consumerSecret,
+		requestTokens:  make(map[string]*pendingToken),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/request_token", p.serveRequestToken)
+	mux.HandleFunc("/oauth/authorize", p.serveAuthorize)
+	mux.HandleFunc("/oauth/access_token", p.serveAccessToken)
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+// Close shuts down the provider's server.
+func (p *Provider) Close() {
+	p.Server.Close()
+}
+
+// Endpoint returns the provider's endpoint URLs.
+func (p *Provider) Endpoint() oauth1.Endpoint {
+	return oauth1.Endpoint{
+		RequestTokenURL: p.Server.URL + "/oauth/request_token",
+		AuthorizeURL:    p.Server.URL + "/oauth/authorize",
+		AccessTokenURL:  p.Server.URL + "/oauth/access_token",
+	}
+}
+
+// Config returns a Config for the provider's consumer credentials and
+// endpoint using the given callback URL.
+func (p *Provider) Config(callbackURL string) *oauth1.Config {
+	return &oauth1.Config{
+		ConsumerKey:    p.ConsumerKey,
+		ConsumerSecret: p.ConsumerSecret,
+		CallbackURL:    callbackURL,
+		Endpoint:       p.Endpoint(),
+	}
+}
+
+// AccessToken returns the most recently issued access token and secret, or
+// empty strings if none has been issued.
+func (p *Provider) AccessToken() (string, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.accessToken, p.accessSecret
+}
+
+// Run completes the three-legged flow with config without involving an
+// application: it obtains a request token, authorizes it as the user, and
+// exchanges the verifier for an access token, which is returned.
+func (p *Provider) Run(config *oauth1.Config) (string, string, error) {
+	requestToken, requestSecret, err := config.RequestToken()
+	if err != nil {
+		return "", "", err
+	}
+	authorizationURL, err := config.AuthorizationURL(requestToken)
+	if err != nil {
+		return "", "", err
+	}
+	res, err := noRedirectClient.Get(authorizationURL.String())
+	if err != nil {
+		return "", "", err
+	}
+	res.Body.Close()
+	location, err := res.Location()
+	if err != nil {
+		return "", "", err
+	}
+	callback := httptest.NewRequest("GET", location.String(), nil)
+	requestToken, verifier, err := oauth1.ParseAuthorizationCallback(callback)
+	if err != nil {
+		return "", "", err
+	}
+	return config.AccessToken(requestToken, requestSecret, verifier)
+}
+
+func (p *Provider) serveRequestToken(w http.ResponseWriter, req *http.Request) {
+	params, err := p.authorize(req, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	callback := params.Get("oauth_callback")
+	if callback == "" {
+		http.Error(w, "oauth_callback missing", http.StatusBadRequest)
+		return
+	}
+	p.mu.Lock()
+	p.issued++
+	n := p.issued
+	token := fmt.Sprintf("request-token-%d", n)
+	pending := &pendingToken{
+		secret:   fmt.Sprintf("request-secret-%d", n),
+		callback: callback,
+		verifier: fmt.Sprintf("verifier-%d", n),
+	}
+	p.requestTokens[token] = pending
+	p.mu.Unlock()
+	writeForm(w, url.Values{
+		"oauth_token":              {token},
+		"oauth_token_secret":       {pending.secret},
+		"oauth_callback_confirmed": {"true"},
+	})
+}
+
+func (p *Provider) serveAuthorize(w http.ResponseWriter, req *http.Request) {
+	token := req.URL.Query().Get("oauth_token")
+	p.mu.Lock()
+	pending, ok := p.requestTokens[token]
+	p.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown oauth_token", http.StatusBadRequest)
+		return
+	}
+	if pending.callback == "oob" {
+		fmt.Fprintf(w, "PIN: %s", pending.verifier)
+		return
+	}
+	callback, err := url.Parse(pending.callback)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := callback.Query()
+	query.Set("oauth_token", token)
+	query.Set("oauth_verifier", pending.verifier)
+	callback.RawQuery = query.Encode()
+	http.Redirect(w, req, callback.String(), http.StatusFound)
+}
+
+func (p *Provider) serveAccessToken(w http.ResponseWriter, req *http.Request) {
+	params, err := p.authorize(req, func(token string) (string, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		pending, ok := p.requestTokens[token]
+		if !ok {
+			return "", errors.New("unknown oauth_token")
+		}
+		return pending.secret, nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	token := params.Get("oauth_token")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending, ok := p.requestTokens[token]
+	if !ok || pending.verifier != params.Get("oauth_verifier") {
+		http.Error(w, "invalid oauth_token or oauth_verifier", http.StatusUnauthorized)
+		return
+	}
+	delete(p.requestTokens, token)
+	p.issued++
+	p.accessToken = fmt.Sprintf("access-token-%d", p.issued)
+	p.accessSecret = fmt.Sprintf("access-secret-%d", p.issued)
+	writeForm(w, url.Values{
+		"oauth_token":        {p.accessToken},
+		"oauth_token_secret": {p.accessSecret},
+	})
+}
+
+// authorize checks that req is a POST signed with the provider's consumer
+// credentials and the token secret returned by tokenSecret, if any, and
+// returns its OAuth parameters. The parameters may be transmitted in the
+// Authorization header, the form body or the query.
+func (p *Provider) authorize(req *http.Request, tokenSecret func(string) (string, error)) (url.Values, error) {
+	if req.Method != "POST" {
+		return nil, errors.New("token requests must be POSTed")
+	}
+	v := &oauth1.Verifier{
+		ConsumerSecret: func(consumerKey string) (string, error) {
+			if consumerKey != p.ConsumerKey {
+				return "", errors.New("unknown oauth_consumer_key")
+			}
+			return p.ConsumerSecret, nil
+		},
+		TokenSecret: tokenSecret,
+	}
+	return v.Verify(req)
+}
+
+func writeForm(w http.ResponseWriter, values url.Values) {
+	w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+	w.Write([]byte(values.Encode()))
+}
+
+var noRedirectClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
@@ -0,0 +1,108 @@
+package oauth1test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ktnyt/oauth1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderRun(t *testing.T) {
+	provider := NewProvider("consumer_key", "consumer_secret")
+	defer provider.Close()
+
+	accessToken, accessSecret, err := provider.Run(provider.Config("http://app.example.com/callback"))
+	assert.Nil(t, err)
+	assert.Equal(t, "access-token-2", accessToken)
+	assert.Equal(t, "access-secret-2", accessSecret)
+}
+
+func TestProviderRun_UnknownConsumer(t *testing.T) {
+	provider := NewProvider("consumer_key", "consumer_secret")
+	defer provider.Close()
+
+	config := provider.Config("http://app.example.com/callback")
+	config.ConsumerKey = "other_key"
+	_, _, err := provider.Run(config)
+	assert.NotNil(t, err)
+}
+
+func TestProviderRun_WrongSecrets(t *testing.T) {
+	provider := NewProvider("consumer_key", "consumer_secret")
+	defer provider.Close()
+
+	config := provider.Config("http://app.example.com/callback")
+	config.ConsumerSecret = "guess"
+	_, _, err := provider.Run(config)
+	assert.NotNil(t, err)
+
+	config = provider.Config("http://app.example.com/callback")
+	requestToken, _, err := config.RequestToken()
+	assert.Nil(t, err)
+	_, _, err = config.AccessToken(requestToken, "guess", "verifier-1")
+	assert.NotNil(t, err)
+}
+
+func TestProviderRun_ParamsInQuery(t *testing.T) {
+	provider := NewProvider("consumer_key", "consumer_secret")
+	defer provider.Close()
+
+	config := provider.Config("http://app.example.com/callback")
+	config.Endpoint.ParamsInQuery = true
+	_, _, err := provider.Run(config)
+	assert.Nil(t, err)
+}
+
+func TestBrowser_AppFlow(t *testing.T) {
+	provider := NewProvider("consumer_key", "consumer_secret")
+	defer provider.Close()
+	config := provider.Config("http://app.example.com/callback")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, req *http.Request) {
+		requestToken, requestSecret, err := config.RequestToken()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "request_secret", Value: requestSecret, Path: "/"})
+		authorizationURL, err := config.AuthorizationURL(requestToken)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, req, authorizationURL.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, req *http.Request) {
+		requestToken, verifier, err := oauth1.ParseAuthorizationCallback(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cookie, err := req.Cookie("request_secret")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		accessToken, _, err := config.AccessToken(requestToken, cookie.Value, verifier)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("welcome " + accessToken))
+	})
+
+	browser := &Browser{Provider: provider, App: mux}
+	res, err := browser.Visit("http://app.example.com/login")
+	if assert.Nil(t, err) {
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "welcome access-token-2", string(body))
+	}
+	accessToken, accessSecret := provider.AccessToken()
+	assert.Equal(t, "access-token-2", accessToken)
+	assert.Equal(t, "access-secret-2", accessSecret)
+}
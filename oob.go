@@ -0,0 +1,71 @@
+package oauth1
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// OOBCallback is the oauth_callback value of the out-of-band flow, in which
+// the provider shows the user a verifier (PIN) to enter into the consumer
+// instead of redirecting to a callback URL. See RFC 5849 2.1.
+const OOBCallback = "oob"
+
+// PINFlow is an out-of-band authorization in progress, for CLI and headless
+// applications which cannot receive callbacks.
+type PINFlow struct {
+	// AuthorizationURL the user visits to authorize the consumer and obtain
+	// the PIN
+	AuthorizationURL *url.URL
+
+	// RequestToken (temporary credentials) of the flow
+	RequestToken *Token
+
+	config *Config
+}
+
+// StartPINFlow obtains a request token with oauth_callback=oob, regardless
+// of the Config's CallbackURL, and returns the flow to complete with the
+// PIN the user is shown after visiting its AuthorizationURL.
+func (c *Config) StartPINFlow() (*PINFlow, error) {
+	oob := c.Clone()
+	oob.CallbackURL = OOBCallback
+	requestToken, err := oob.RequestTokenPair()
+	if err != nil {
+		return nil, err
+	}
+	authorizationURL, err := oob.AuthorizationURL(requestToken.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &PINFlow{AuthorizationURL: authorizationURL, RequestToken: requestToken, config: oob}, nil
+}
+
+// Complete exchanges the request token and the PIN entered by the user, used
+// as the verifier, for an access token.
+func (f *PINFlow) Complete(pin string) (*Token, error) {
+	pin = strings.TrimSpace(pin)
+	if pin == "" {
+		return nil, errors.New("oauth1: PIN is empty")
+	}
+	return f.config.AccessTokenPair(f.RequestToken, pin)
+}
+
+// AuthorizePIN runs the out-of-band flow interactively: it writes the
+// authorization URL to w, reads the PIN the user enters as a line from r and
+// returns the access token.
+func (c *Config) AuthorizePIN(r io.Reader, w io.Writer) (*Token, error) {
+	flow, err := c.StartPINFlow()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w, "Open this URL in your browser and authorize the application:\n%s\nEnter the PIN: ", flow.AuthorizationURL)
+	pin, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return flow.Complete(pin)
+}
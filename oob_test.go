@@ -0,0 +1,74 @@
+package oauth1
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newPINServer returns a Config for a provider running the out-of-band flow
+// which accepts the PIN 1234567.
+func newPINServer(t *testing.T) *Config {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/request_token", func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, OOBCallback, params["oauth_callback"])
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"))
+	})
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		if params["oauth_token"] != "request_token" || params["oauth_verifier"] != "1234567" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte(url.Values{
+			"oauth_token":        {"access_token"},
+			"oauth_token_secret": {"access_secret"},
+		}.Encode()))
+	})
+	server := newMockServer(mux.ServeHTTP)
+	t.Cleanup(server.Close)
+	return &Config{
+		ConsumerKey: "consumer_key",
+		CallbackURL: "https://app.example.com/callback",
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL + "/oauth/request_token",
+			AuthorizeURL:    server.URL + "/oauth/authorize",
+			AccessTokenURL:  server.URL + "/oauth/access_token",
+		},
+	}
+}
+
+func TestConfigStartPINFlow(t *testing.T) {
+	config := newPINServer(t)
+	flow, err := config.StartPINFlow()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "request_token", flow.AuthorizationURL.Query().Get("oauth_token"))
+	assert.Equal(t, "request_token", flow.RequestToken.Token)
+	assert.Equal(t, "https://app.example.com/callback", config.CallbackURL)
+
+	_, err = flow.Complete("")
+	assert.Error(t, err)
+	_, err = flow.Complete("7654321")
+	assert.Error(t, err)
+	token, err := flow.Complete(" 1234567\n")
+	assert.Nil(t, err)
+	assert.Equal(t, &Token{Token: "access_token", TokenSecret: "access_secret"}, token)
+}
+
+func TestConfigAuthorizePIN(t *testing.T) {
+	config := newPINServer(t)
+	var out bytes.Buffer
+	token, err := config.AuthorizePIN(strings.NewReader("1234567\n"), &out)
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", token.Token)
+	assert.Contains(t, out.String(), config.Endpoint.AuthorizeURL+"?oauth_token=request_token")
+}
@@ -0,0 +1,69 @@
+package oauth1
+
+import (
+	"net/http"
+	"time"
+)
+
+// An Option configures a Config created by NewConfig.
+type Option func(*Config)
+
+// NewConfig returns a Config with the given consumer credentials and
+// options applied in order.
+func NewConfig(consumerKey, consumerSecret string, opts ...Option) *Config {
+	c := &Config{ConsumerKey: consumerKey, ConsumerSecret: consumerSecret}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithEndpoint sets the provider Endpoint.
+func WithEndpoint(endpoint Endpoint) Option {
+	return func(c *Config) {
+		c.Endpoint = endpoint
+	}
+}
+
+// WithCallbackURL sets the Callback URL sent with request token requests.
+func WithCallbackURL(callbackURL string) Option {
+	return func(c *Config) {
+		c.CallbackURL = callbackURL
+	}
+}
+
+// WithHTTPClient sets the *http.Client used for token endpoint requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// WithTimeout sets the timeout of token endpoint requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithSigner sets the Signer computing signatures, selecting the signature
+// method.
+func WithSigner(signer Signer) Option {
+	return func(c *Config) {
+		c.Signer = signer
+	}
+}
+
+// WithClock sets the Clock providing oauth_timestamp values.
+func WithClock(clock func() time.Time) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithNoncer sets the Noncer generating oauth_nonce values.
+func WithNoncer(noncer Noncer) Option {
+	return func(c *Config) {
+		c.Noncer = noncer
+	}
+}
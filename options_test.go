@@ -0,0 +1,58 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig(t *testing.T) {
+	endpoint := Endpoint{
+		RequestTokenURL: "https://example.com/oauth/request_token",
+		AuthorizeURL:    "https://example.com/oauth/authorize",
+		AccessTokenURL:  "https://example.com/oauth/access_token",
+	}
+	noncer := NewCounterNoncer()
+	config := NewConfig("consumer_key", "consumer_secret",
+		WithEndpoint(endpoint),
+		WithCallbackURL("https://app.example.com/callback"),
+		WithNoncer(noncer),
+	)
+	assert.Equal(t, "consumer_key", config.ConsumerKey)
+	assert.Equal(t, "consumer_secret", config.ConsumerSecret)
+	assert.Equal(t, endpoint, config.Endpoint)
+	assert.Equal(t, "https://app.example.com/callback", config.CallbackURL)
+	assert.Equal(t, noncer, config.Noncer)
+	assert.Nil(t, config.Context)
+}
+
+func TestNewConfig_WithHTTPClient(t *testing.T) {
+	var used bool
+	client := &http.Client{Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		used = true
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	data := url.Values{}
+	data.Add("oauth_token", "request_token")
+	data.Add("oauth_token_secret", "request_secret")
+	data.Add("oauth_callback_confirmed", "true")
+	server := newRequestTokenServer(t, data)
+	defer server.Close()
+
+	config := NewConfig("consumer_key", "consumer_secret",
+		WithEndpoint(Endpoint{RequestTokenURL: server.URL}),
+		WithHTTPClient(client),
+	)
+	_, _, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.True(t, used)
+}
+
+func TestNewConfig_WithTimeout(t *testing.T) {
+	config := NewConfig("consumer_key", "consumer_secret", WithTimeout(5*time.Second))
+	assert.Equal(t, 5*time.Second, config.Timeout)
+}
@@ -0,0 +1,170 @@
+package oauth1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pool signs large numbers of requests concurrently with a bounded number of
+// workers, reusing HMAC-SHA1 hashers between signatures unless the consumer
+// secret comes from a SecretProvider. It suits crawlers and
+// exporters which prepare many requests ahead of sending them. A Pool is safe
+// for concurrent use.
+type Pool struct {
+	signing     signing
+	noncer      Noncer
+	clock       func() time.Time
+	oauthParams url.Values
+	tokenSecret string
+	workers     int
+}
+
+// NewPool returns a Pool signing requests with config's consumer credentials
+// and token, nil for two-legged requests, using workers goroutines (at least
+// one).
+func NewPool(config *Config, token *Token, workers int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	oauthParams, tokenSecret := tokenParams(token)
+	p := &Pool{
+		signing:     config.signing(),
+		noncer:      config.Noncer,
+		clock:       config.Clock,
+		oauthParams: oauthParams,
+		tokenSecret: tokenSecret,
+		workers:     workers,
+	}
+	if _, ok := p.signing.method().(HMACSigner); ok && p.signing.secrets == nil {
+		key := []byte(strings.Join([]string{percentEncode(config.ConsumerSecret), percentEncode(tokenSecret)}, "&"))
+		p.signing.signer = &pooledHMACSigner{hashers: sync.Pool{
+			New: func() interface{} { return hmac.New(sha1.New, key) },
+		}}
+	}
+	return p
+}
+
+// PoolResult is the outcome of signing one request.
+type PoolResult struct {
+	Request *http.Request
+	Err     error
+}
+
+// poolJob is a request for a worker to sign, and where to deliver the
+// result.
+type poolJob struct {
+	req    *http.Request
+	result chan<- PoolResult
+}
+
+// Sign signs the requests received from in, adding their Authorization
+// header in place, or their protocol parameters to the query if the Endpoint
+// has ParamsInQuery set, and delivers them to the returned channel in the
+// order they were received. At most as many requests as the Pool has workers
+// are signed or waiting to be delivered at any time, so a slow consumer holds
+// back reading from in. The returned channel is closed once in is closed and
+// drained, or ctx is done.
+func (p *Pool) Sign(ctx context.Context, in <-chan *http.Request) <-chan PoolResult {
+	out := make(chan PoolResult)
+	jobs := make(chan poolJob)
+	// the collector below holds one pending result, the buffer the others
+	pending := make(chan chan PoolResult, p.workers-1)
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			for job := range jobs {
+				job.result <- PoolResult{job.req, p.sign(job.req)}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for {
+			var req *http.Request
+			var ok bool
+			select {
+			case req, ok = <-in:
+			case <-ctx.Done():
+				return
+			}
+			if !ok {
+				return
+			}
+			result := make(chan PoolResult, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+			// a worker is free, as pending bounds the requests in flight
+			jobs <- poolJob{req, result}
+		}
+	}()
+	go func() {
+		defer close(out)
+		for result := range pending {
+			select {
+			case out <- <-result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// SignAll signs reqs in place, returning the first error encountered.
+func (p *Pool) SignAll(reqs []*http.Request) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan *http.Request)
+	go func() {
+		defer close(in)
+		for _, req := range reqs {
+			select {
+			case in <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for result := range p.Sign(ctx, in) {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return nil
+}
+
+func (p *Pool) sign(req *http.Request) error {
+	st := stamp{newNonce(p.noncer), signingTime(p.clock)}
+	return p.signing.authorize(req, p.tokenSecret, p.oauthParams, st)
+}
+
+// pooledHMACSigner is an HMACSigner for a fixed key, reusing its hashers.
+type pooledHMACSigner struct {
+	hashers sync.Pool
+}
+
+// Name returns "HMAC-SHA1".
+func (*pooledHMACSigner) Name() string {
+	return "HMAC-SHA1"
+}
+
+// Sign returns the base64 encoded HMAC-SHA1 digest of base made with the
+// key of the pooled hashers, which key must equal.
+func (s *pooledHMACSigner) Sign(key, base string) (string, error) {
+	h := s.hashers.Get().(hash.Hash)
+	defer s.hashers.Put(h)
+	h.Reset()
+	h.Write([]byte(base))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
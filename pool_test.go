@@ -0,0 +1,117 @@
+package oauth1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolSignAll(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	token := &Token{Token: "token", TokenSecret: "token_secret"}
+	pool := NewPool(config, token, 4)
+
+	var reqs []*http.Request
+	for i := 0; i < 100; i++ {
+		req, err := http.NewRequest("GET", fmt.Sprintf("https://api.example.com/items/%d", i), nil)
+		assert.Nil(t, err)
+		reqs = append(reqs, req)
+	}
+	assert.Nil(t, pool.SignAll(reqs))
+
+	for _, req := range reqs {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "token", params["oauth_token"])
+
+		// the pooled signature matches the one of a plain signing
+		expected, err := config.signing().authorizationHeader(
+			req, token.TokenSecret, map[string][]string{"oauth_token": {token.Token}},
+			stamp{params["oauth_nonce"], time.Unix(mustParseInt(t, params["oauth_timestamp"]), 0)})
+		assert.Nil(t, err)
+		assert.Equal(t, parseOAuthParamsOrFail(t, expected)["oauth_signature"], params["oauth_signature"])
+	}
+}
+
+func TestPoolSign_Order(t *testing.T) {
+	pool := NewPool(&Config{ConsumerKey: "consumer_key"}, &Token{}, 8)
+	in := make(chan *http.Request)
+	go func() {
+		defer close(in)
+		for i := 0; i < 50; i++ {
+			req, _ := http.NewRequest("GET", fmt.Sprintf("https://api.example.com/items/%d", i), nil)
+			in <- req
+		}
+	}()
+	i := 0
+	for result := range pool.Sign(context.Background(), in) {
+		assert.Nil(t, result.Err)
+		assert.Equal(t, fmt.Sprintf("/items/%d", i), result.Request.URL.Path)
+		i++
+	}
+	assert.Equal(t, 50, i)
+}
+
+func TestPoolSign_Error(t *testing.T) {
+	pool := NewPool(&Config{ConsumerKey: "consumer_key"}, &Token{}, 2)
+	req, err := http.NewRequest("POST", "https://api.example.com/items", strings.NewReader("%gh&%ij"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Error(t, pool.SignAll([]*http.Request{req}))
+}
+
+func TestPoolSign_Cancel(t *testing.T) {
+	pool := NewPool(&Config{ConsumerKey: "consumer_key"}, &Token{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan *http.Request)
+	out := pool.Sign(ctx, in)
+	cancel()
+	select {
+	case _, ok := <-out:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Error("expected the results channel to close after cancellation")
+	}
+}
+
+func mustParseInt(t *testing.T, s string) int64 {
+	var n int64
+	_, err := fmt.Sscan(s, &n)
+	assert.Nil(t, err)
+	return n
+}
+
+func TestPoolSignAll_ParamsInQuery(t *testing.T) {
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		Endpoint:       Endpoint{ParamsInQuery: true},
+		ExtraParams:    url.Values{"xoauth_requestor_id": {"alice"}},
+	}
+	pool := NewPool(config, nil, 2)
+	req, err := http.NewRequest("GET", "https://api.example.com/items?page=2", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, pool.SignAll([]*http.Request{req}))
+
+	assert.Empty(t, req.Header.Get("Authorization"))
+	query := req.URL.Query()
+	assert.Equal(t, "2", query.Get("page"))
+	assert.Equal(t, "alice", query.Get("xoauth_requestor_id"))
+	assert.NotContains(t, query, "oauth_token")
+
+	// the pooled signature matches the one of a plain signing
+	unsigned, err := http.NewRequest("GET", "https://api.example.com/items?page=2", nil)
+	assert.Nil(t, err)
+	expected, err := config.signing().authorizationHeader(
+		unsigned, "", nil,
+		stamp{query.Get("oauth_nonce"), time.Unix(mustParseInt(t, query.Get("oauth_timestamp")), 0)})
+	assert.Nil(t, err)
+	signature, err := url.QueryUnescape(parseOAuthParamsOrFail(t, expected)["oauth_signature"])
+	assert.Nil(t, err)
+	assert.Equal(t, signature, query.Get("oauth_signature"))
+}
@@ -0,0 +1,109 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Problem is an error reported by a provider per the OAuth Problem Reporting
+// extension, see http://wiki.oauth.net/w/page/12238543/ProblemReporting.
+type Problem struct {
+	// Problem code, e.g. "timestamp_refused" or "token_expired"
+	Problem string
+
+	// Advice is a human readable explanation, if the provider gave one
+	Advice string
+
+	// AcceptableTimestamps is the range of timestamps the provider accepts,
+	// zero if not reported
+	AcceptableTimestamps [2]int64
+
+	// ParametersAbsent and ParametersRejected name the parameters that
+	// were missing from or refused in the request
+	ParametersAbsent   []string
+	ParametersRejected []string
+}
+
+// parseProblem returns the Problem reported in the WWW-Authenticate header
+// or the form-encoded body of a token endpoint error response, or nil.
+func parseProblem(header http.Header, body []byte) *Problem {
+	var params url.Values
+	for _, challenge := range header["Www-Authenticate"] {
+		if p, err := ParseOAuthHeader(challenge); err == nil && p.Get("oauth_problem") != "" {
+			params = p
+			break
+		}
+	}
+	if params == nil {
+		p, err := url.ParseQuery(strings.TrimSpace(string(body)))
+		if err != nil || p.Get("oauth_problem") == "" {
+			return nil
+		}
+		params = p
+	}
+	problem := &Problem{
+		Problem:            params.Get("oauth_problem"),
+		Advice:             params.Get("oauth_problem_advice"),
+		ParametersAbsent:   splitList(params.Get("oauth_parameters_absent")),
+		ParametersRejected: splitList(params.Get("oauth_parameters_rejected")),
+	}
+	if timestamps := strings.SplitN(params.Get("oauth_acceptable_timestamps"), "-", 2); len(timestamps) == 2 {
+		min, minErr := strconv.ParseInt(timestamps[0], 10, 64)
+		max, maxErr := strconv.ParseInt(timestamps[1], 10, 64)
+		if minErr == nil && maxErr == nil {
+			problem.AcceptableTimestamps = [2]int64{min, max}
+		}
+	}
+	return problem
+}
+
+// splitList splits a list of parameter names joined by "&", nil if empty.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "&")
+}
+
+// Values returns p as oauth_problem parameters, the inverse of the parsing
+// of a token endpoint error response, for a provider reporting p in a
+// form-encoded body or a WWW-Authenticate header.
+func (p *Problem) Values() url.Values {
+	params := url.Values{"oauth_problem": {p.Problem}}
+	if p.Advice != "" {
+		params.Set("oauth_problem_advice", p.Advice)
+	}
+	if p.AcceptableTimestamps != [2]int64{} {
+		params.Set("oauth_acceptable_timestamps", strconv.FormatInt(p.AcceptableTimestamps[0], 10)+"-"+strconv.FormatInt(p.AcceptableTimestamps[1], 10))
+	}
+	if len(p.ParametersAbsent) > 0 {
+		params.Set("oauth_parameters_absent", strings.Join(p.ParametersAbsent, "&"))
+	}
+	if len(p.ParametersRejected) > 0 {
+		params.Set("oauth_parameters_rejected", strings.Join(p.ParametersRejected, "&"))
+	}
+	return params
+}
+
+// WriteProblem reports p to a consumer per the Problem Reporting extension:
+// in an OAuth WWW-Authenticate challenge with realm and as a form-encoded
+// body, with status 400 Bad Request for malformed requests and 401
+// Unauthorized otherwise. See RFC 5849 3.2.
+func WriteProblem(w http.ResponseWriter, realm string, p *Problem) {
+	params := p.Values()
+	w.Header().Set("WWW-Authenticate", FormatOAuthHeader(realm, params))
+	w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+	w.WriteHeader(problemStatus(p.Problem))
+	w.Write([]byte(params.Encode()))
+}
+
+// problemStatus returns the status code of a response reporting problem.
+func problemStatus(problem string) int {
+	switch problem {
+	case "parameter_absent", "parameter_rejected", "signature_method_rejected", "version_rejected":
+		return http.StatusBadRequest
+	}
+	return http.StatusUnauthorized
+}
@@ -0,0 +1,81 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProblem_Body(t *testing.T) {
+	body := []byte("oauth_problem=parameter_absent&oauth_parameters_absent=oauth_nonce%26oauth_timestamp&oauth_problem_advice=Missing+parameters")
+	problem := parseProblem(http.Header{}, body)
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, "parameter_absent", problem.Problem)
+		assert.Equal(t, "Missing parameters", problem.Advice)
+		assert.Equal(t, []string{"oauth_nonce", "oauth_timestamp"}, problem.ParametersAbsent)
+	}
+}
+
+func TestParseProblem_WWWAuthenticate(t *testing.T) {
+	header := http.Header{}
+	header.Set("WWW-Authenticate", `OAuth realm="https://api.example.com/", oauth_problem="timestamp_refused", oauth_acceptable_timestamps="1318467000-1318467900"`)
+	problem := parseProblem(header, []byte("<html>Unauthorized</html>"))
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, "timestamp_refused", problem.Problem)
+		assert.Equal(t, [2]int64{1318467000, 1318467900}, problem.AcceptableTimestamps)
+	}
+}
+
+func TestParseProblem_None(t *testing.T) {
+	assert.Nil(t, parseProblem(http.Header{}, []byte("Invalid consumer key")))
+	assert.Nil(t, parseProblem(http.Header{}, []byte("%gh&%ij")))
+}
+
+func TestTokenRequestError_Problem(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("oauth_problem=token_expired"))
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{AccessTokenURL: server.URL}}
+	_, _, err := config.AccessToken("request_token", "request_secret", expectedVerifier)
+	var tokenErr *TokenRequestError
+	if assert.True(t, errors.As(err, &tokenErr)) && assert.NotNil(t, tokenErr.Problem) {
+		assert.Equal(t, "token_expired", tokenErr.Problem.Problem)
+		assert.Equal(t, "oauth1: Server returned unexpected status 401: token_expired", err.Error())
+	}
+}
+
+func TestProblem_Values(t *testing.T) {
+	problem := &Problem{
+		Problem:              "timestamp_refused",
+		AcceptableTimestamps: [2]int64{1318467000, 1318467900},
+	}
+	params := problem.Values()
+	assert.Equal(t, "timestamp_refused", params.Get("oauth_problem"))
+	assert.Equal(t, "1318467000-1318467900", params.Get("oauth_acceptable_timestamps"))
+	assert.NotContains(t, params, "oauth_problem_advice")
+
+	problem = &Problem{Problem: "parameter_absent", ParametersAbsent: []string{"oauth_nonce", "oauth_timestamp"}}
+	assert.Equal(t, problem, parseProblem(http.Header{}, []byte(problem.Values().Encode())))
+}
+
+func TestWriteProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteProblem(w, "Example", &Problem{Problem: "nonce_used"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `OAuth realm="Example", oauth_problem="nonce_used"`, w.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, "oauth_problem=nonce_used", w.Body.String())
+
+	w = httptest.NewRecorder()
+	WriteProblem(w, "", &Problem{Problem: "parameter_absent", ParametersAbsent: []string{"oauth_nonce"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	problem := parseProblem(w.Header(), nil)
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, []string{"oauth_nonce"}, problem.ParametersAbsent)
+	}
+}
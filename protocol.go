@@ -0,0 +1,15 @@
+package oauth1
+
+// Protocol is the revision of the OAuth 1 protocol a provider implements.
+type Protocol int
+
+const (
+	// OAuth10a is OAuth 1.0 Revision A (RFC 5849), which sends
+	// oauth_callback with the request token request and oauth_verifier with
+	// the access token request. It is the default.
+	OAuth10a Protocol = iota
+
+	// OAuth10 is the original OAuth Core 1.0, which has neither parameter
+	// and passes oauth_callback to the authorization page instead.
+	OAuth10
+)
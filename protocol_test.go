@@ -0,0 +1,50 @@
+package oauth1
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_OAuth10(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/request_token", func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.NotContains(t, params, "oauth_callback")
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret"))
+	})
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "request_token", params["oauth_token"])
+		assert.NotContains(t, params, "oauth_verifier")
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=access_token&oauth_token_secret=access_secret"))
+	})
+	server := newMockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	config := &Config{
+		ConsumerKey: "consumer_key",
+		CallbackURL: "https://app.example.com/callback",
+		Protocol:    OAuth10,
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL + "/oauth/request_token",
+			AuthorizeURL:    server.URL + "/oauth/authorize",
+			AccessTokenURL:  server.URL + "/oauth/access_token",
+		},
+	}
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+
+	authorizationURL, err := config.AuthorizationURL(requestToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://app.example.com/callback", authorizationURL.Query().Get("oauth_callback"))
+
+	accessToken, accessSecret, err := config.AccessToken(requestToken, requestSecret, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", accessToken)
+	assert.Equal(t, "access_secret", accessSecret)
+}
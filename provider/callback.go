@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NewVerifier returns a new oauth_verifier, 32 characters drawn from
+// crypto/rand, which consumers cannot guess.
+func NewVerifier() string {
+	return newCredential()
+}
+
+// CallbackURL returns the callback URL of the consumer with oauth_token and
+// the oauth_verifier bound to it added to its query, retaining any query
+// parameters of the callback. Callbacks other than absolute http or https
+// URLs are rejected. See RFC 5849 2.2.
+func CallbackURL(token *RequestToken) (*url.URL, error) {
+	if !isHTTPURL(token.Callback) {
+		return nil, fmt.Errorf("provider: Callback %q is not an absolute http or https URL", token.Callback)
+	}
+	callback, err := url.Parse(token.Callback)
+	if err != nil {
+		return nil, err
+	}
+	query := callback.Query()
+	query.Set("oauth_token", token.Token)
+	query.Set("oauth_verifier", token.Verifier)
+	callback.RawQuery = query.Encode()
+	return callback, nil
+}
+
+// RedirectToCallback completes the authorization of token by redirecting
+// the user to the consumer's CallbackURL or, for "oob" callbacks, showing
+// the verifier for the user to enter at the consumer. Invalid callbacks are
+// answered with 400 Bad Request.
+func RedirectToCallback(w http.ResponseWriter, req *http.Request, token *RequestToken) {
+	if token.Callback == "oob" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "Verifier: %s\n", token.Verifier)
+		return
+	}
+	callback, err := CallbackURL(token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Redirect(w, req, callback.String(), http.StatusFound)
+}
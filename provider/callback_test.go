@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerifier(t *testing.T) {
+	verifier := NewVerifier()
+	assert.Len(t, verifier, 32)
+	assert.NotEqual(t, verifier, NewVerifier())
+}
+
+func TestCallbackURL(t *testing.T) {
+	callback, err := CallbackURL(&RequestToken{
+		Token:    "request token",
+		Verifier: "verifier",
+		Callback: "https://app.example.com/callback?state=a%20b",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "https://app.example.com/callback?oauth_token=request+token&oauth_verifier=verifier&state=a+b", callback.String())
+
+	for _, c := range []string{"/callback", "javascript:alert(1)", "data:text/html,hi", "https:///callback"} {
+		_, err = CallbackURL(&RequestToken{Callback: c})
+		assert.Error(t, err, c)
+	}
+}
+
+func TestRedirectToCallback(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://api.example.com/oauth/authorize", nil)
+	w := httptest.NewRecorder()
+	RedirectToCallback(w, req, &RequestToken{Token: "token", Verifier: "verifier", Callback: "https://app.example.com/callback"})
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://app.example.com/callback?oauth_token=token&oauth_verifier=verifier", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	RedirectToCallback(w, req, &RequestToken{Token: "token", Verifier: "verifier", Callback: "oob"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "verifier")
+
+	w = httptest.NewRecorder()
+	RedirectToCallback(w, req, &RequestToken{Token: "token", Verifier: "verifier", Callback: "javascript:alert(1)"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+}
@@ -0,0 +1,234 @@
+// Package provider implements the endpoints of an OAuth1 provider: the
+// temporary credential request, resource owner authorization and token
+// request endpoints of RFC 5849 section 2.
+//
+// A Provider verifies the signed token requests of consumers, issues
+// credentials and persists them in a RequestTokenStore and an
+// AccessTokenStore, and leaves authenticating the user and asking for
+// consent to the application.
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ktnyt/oauth1"
+)
+
+// DefaultRequestTokenTTL is how long temporary credentials may be authorized
+// and exchanged when a Provider has no RequestTokenTTL.
+const DefaultRequestTokenTTL = 15 * time.Minute
+
+// Provider serves the endpoints of an OAuth1 provider.
+type Provider struct {
+	Consumers     oauth1.ConsumerStore
+	RequestTokens RequestTokenStore
+	AccessTokens  AccessTokenStore
+
+	// Authorize authenticates the user of the resource owner authorization
+	// endpoint and asks for consent to grant token. It returns the ID of
+	// the user granting access, or false if the user has not (yet) granted
+	// it, in which case Authorize has written the response itself, such as
+	// a login page, a consent form or an error.
+	Authorize func(w http.ResponseWriter, req *http.Request, token *RequestToken) (userID string, ok bool)
+
+	// Verifier configures the verification of token requests, such as its
+	// Nonces and TimestampWindow. Its Consumers and TokenSecret are set by
+	// the Provider.
+	Verifier oauth1.Verifier
+
+	// RequestTokenTTL is how long temporary credentials are valid,
+	// DefaultRequestTokenTTL if zero
+	RequestTokenTTL time.Duration
+
+	// Realm of the WWW-Authenticate challenges reporting problems with
+	// token requests
+	Realm string
+}
+
+// RequestTokenHandler returns the temporary credential request endpoint,
+// issuing request tokens to consumers POSTing a signed request with an
+// oauth_callback, an http or https URL or "oob". See RFC 5849 2.1.
+func (p *Provider) RequestTokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		params, err := p.verify(req, nil)
+		if err != nil {
+			p.writeVerificationError(w, err)
+			return
+		}
+		callback := params.Get("oauth_callback")
+		if callback == "" {
+			p.writeProblem(w, &oauth1.Problem{Problem: "parameter_absent", ParametersAbsent: []string{"oauth_callback"}})
+			return
+		}
+		if callback != "oob" && !isHTTPURL(callback) {
+			p.writeProblem(w, &oauth1.Problem{
+				Problem:            "parameter_rejected",
+				Advice:             "oauth_callback must be an absolute http or https URL or oob",
+				ParametersRejected: []string{"oauth_callback"},
+			})
+			return
+		}
+		token := &RequestToken{
+			ConsumerKey: params.Get("oauth_consumer_key"),
+			Token:       newCredential(),
+			TokenSecret: newCredential(),
+			Callback:    callback,
+			Expires:     p.now().Add(p.requestTokenTTL()),
+		}
+		if err := p.RequestTokens.IssueRequestToken(token); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeForm(w, url.Values{
+			"oauth_token":              {token.Token},
+			"oauth_token_secret":       {token.TokenSecret},
+			"oauth_callback_confirmed": {"true"},
+		})
+	})
+}
+
+// AuthorizeHandler returns the resource owner authorization endpoint which
+// users are directed to with an oauth_token. Once Authorize reports the
+// user's consent, a verifier is bound to the request token and the user is
+// redirected to the consumer's callback, or shown the verifier for "oob"
+// callbacks. Request tokens can be authorized once. See RFC 5849 2.2.
+func (p *Provider) AuthorizeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token, err := p.RequestTokens.RequestToken(req.FormValue("oauth_token"))
+		if err != nil || !p.now().Before(token.Expires) {
+			http.Error(w, "invalid or expired oauth_token", http.StatusBadRequest)
+			return
+		}
+		userID, ok := p.Authorize(w, req, token)
+		if !ok {
+			return
+		}
+		token.UserID, token.Verifier = userID, NewVerifier()
+		if err := p.RequestTokens.BindVerifier(token.Token, token.UserID, token.Verifier); errors.Is(err, ErrTokenAuthorized) || errors.Is(err, ErrUnknownToken) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		RedirectToCallback(w, req, token)
+	})
+}
+
+// AccessTokenHandler returns the token request endpoint, exchanging
+// authorized request tokens and their oauth_verifier for access tokens.
+// Request tokens can be exchanged once. See RFC 5849 2.3.
+func (p *Provider) AccessTokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var token *RequestToken
+		params, err := p.verify(req, func(t string) (string, error) {
+			var err error
+			if token, err = p.RequestTokens.RequestToken(t); err != nil {
+				return "", err
+			}
+			return token.TokenSecret, nil
+		})
+		if err != nil {
+			p.writeVerificationError(w, err)
+			return
+		}
+		if token == nil || token.ConsumerKey != params.Get("oauth_consumer_key") {
+			p.writeProblem(w, &oauth1.Problem{Problem: "token_rejected"})
+			return
+		}
+		if !p.now().Before(token.Expires) {
+			p.writeProblem(w, &oauth1.Problem{Problem: "token_expired"})
+			return
+		}
+		// check the verifier and exchange the token at once, so that the
+		// token is exchanged once and for the user who authorized it
+		token, err = p.RequestTokens.ExchangeRequestToken(token.Token, params.Get("oauth_verifier"))
+		if errors.Is(err, ErrVerifierMismatch) {
+			p.writeProblem(w, &oauth1.Problem{Problem: "parameter_rejected", ParametersRejected: []string{"oauth_verifier"}})
+			return
+		} else if err != nil {
+			p.writeProblem(w, &oauth1.Problem{Problem: "token_used"})
+			return
+		}
+		access := &AccessToken{
+			ConsumerKey: token.ConsumerKey,
+			Token:       newCredential(),
+			TokenSecret: newCredential(),
+			UserID:      token.UserID,
+		}
+		if err := p.AccessTokens.IssueAccessToken(access); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeForm(w, url.Values{
+			"oauth_token":        {access.Token},
+			"oauth_token_secret": {access.TokenSecret},
+		})
+	})
+}
+
+// verify verifies the signature of req with the consumer secrets of the
+// Provider and the token secrets returned by tokenSecret.
+func (p *Provider) verify(req *http.Request, tokenSecret func(string) (string, error)) (url.Values, error) {
+	v := p.Verifier
+	v.Consumers = p.Consumers
+	v.TokenSecret = tokenSecret
+	return v.Verify(req)
+}
+
+// writeVerificationError reports the problem of a failed verification.
+func (p *Provider) writeVerificationError(w http.ResponseWriter, err error) {
+	var verr *oauth1.VerificationError
+	if !errors.As(err, &verr) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	p.writeProblem(w, &verr.Problem)
+}
+
+// writeProblem reports problem to the consumer in the Provider's Realm.
+func (p *Provider) writeProblem(w http.ResponseWriter, problem *oauth1.Problem) {
+	oauth1.WriteProblem(w, p.Realm, problem)
+}
+
+func (p *Provider) requestTokenTTL() time.Duration {
+	if p.RequestTokenTTL > 0 {
+		return p.RequestTokenTTL
+	}
+	return DefaultRequestTokenTTL
+}
+
+func (p *Provider) now() time.Time {
+	if p.Verifier.Clock != nil {
+		return p.Verifier.Clock()
+	}
+	return time.Now()
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL, which users
+// can safely be redirected to unlike, say, javascript: URLs.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// newCredential returns a random token, secret or verifier.
+func newCredential() string {
+	return oauth1.RandomNoncer{}.Nonce()
+}
+
+func writeForm(w http.ResponseWriter, values url.Values) {
+	w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+	w.Write([]byte(values.Encode()))
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ktnyt/oauth1"
+	"github.com/stretchr/testify/assert"
+)
+
+type consumers map[string]string
+
+func (c consumers) Consumer(consumerKey string) (*oauth1.Consumer, error) {
+	secret, ok := c[consumerKey]
+	if !ok {
+		return nil, errors.New("unknown consumer")
+	}
+	return &oauth1.Consumer{Key: consumerKey, Secret: secret}, nil
+}
+
+func newTestProvider() *Provider {
+	tokens := NewMemoryTokenStore()
+	return &Provider{Consumers: consumers{"consumer_key": "consumer_secret"}, RequestTokens: tokens, AccessTokens: tokens}
+}
+
+// newTestServer serves p, granting access as alice unless the request
+// carries deny=1.
+func newTestServer(p *Provider) *httptest.Server {
+	p.Authorize = func(w http.ResponseWriter, req *http.Request, token *RequestToken) (string, bool) {
+		if req.FormValue("deny") != "" {
+			http.Error(w, "access denied", http.StatusForbidden)
+			return "", false
+		}
+		return "alice", true
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/oauth/request_token", p.RequestTokenHandler())
+	mux.Handle("/oauth/authorize", p.AuthorizeHandler())
+	mux.Handle("/oauth/access_token", p.AccessTokenHandler())
+	return httptest.NewServer(mux)
+}
+
+func testConfig(server *httptest.Server, callback string) *oauth1.Config {
+	return &oauth1.Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		CallbackURL:    callback,
+		Endpoint: oauth1.Endpoint{
+			RequestTokenURL: server.URL + "/oauth/request_token",
+			AuthorizeURL:    server.URL + "/oauth/authorize",
+			AccessTokenURL:  server.URL + "/oauth/access_token",
+		},
+	}
+}
+
+var noRedirectClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// assertProblem asserts that err reports problem and returns it.
+func assertProblem(t *testing.T, err error, problem string) *oauth1.Problem {
+	var terr *oauth1.TokenRequestError
+	if assert.True(t, errors.As(err, &terr), "%v", err) && assert.NotNil(t, terr.Problem) {
+		assert.Equal(t, problem, terr.Problem.Problem)
+		return terr.Problem
+	}
+	return &oauth1.Problem{}
+}
+
+func TestProvider_Flow(t *testing.T) {
+	tokens := NewMemoryTokenStore()
+	p := &Provider{Consumers: consumers{"consumer_key": "consumer_secret"}, RequestTokens: tokens, AccessTokens: tokens}
+	server := newTestServer(p)
+	defer server.Close()
+	config := testConfig(server, "https://app.example.com/callback?state=1")
+
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.Nil(t, err)
+	authorizationURL, err := config.AuthorizationURL(requestToken)
+	assert.Nil(t, err)
+	res, err := noRedirectClient.Get(authorizationURL.String())
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	location, err := res.Location()
+	assert.Nil(t, err)
+	assert.Equal(t, "app.example.com", location.Host)
+	assert.Equal(t, "1", location.Query().Get("state"))
+
+	callbackToken, verifier, err := oauth1.ParseAuthorizationCallback(httptest.NewRequest("GET", location.String(), nil))
+	assert.Nil(t, err)
+	assert.Equal(t, requestToken, callbackToken)
+
+	// a second authorization does not rebind the token
+	res, err = noRedirectClient.Get(authorizationURL.String())
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// a wrong verifier is rejected
+	_, _, err = config.AccessToken(requestToken, requestSecret, "guess")
+	problem := assertProblem(t, err, "parameter_rejected")
+	assert.Equal(t, []string{"oauth_verifier"}, problem.ParametersRejected)
+
+	accessToken, accessSecret, err := config.AccessToken(requestToken, requestSecret, verifier)
+	assert.Nil(t, err)
+	access, err := tokens.AccessToken(accessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, accessSecret, access.TokenSecret)
+	assert.Equal(t, "alice", access.UserID)
+	assert.Equal(t, "consumer_key", access.ConsumerKey)
+
+	// request tokens are exchanged once
+	_, _, err = config.AccessToken(requestToken, requestSecret, verifier)
+	assertProblem(t, err, "token_rejected")
+}
+
+func TestProvider_OOB(t *testing.T) {
+	p := newTestProvider()
+	server := newTestServer(p)
+	defer server.Close()
+	config := testConfig(server, "oob")
+
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.Nil(t, err)
+	authorizationURL, err := config.AuthorizationURL(requestToken)
+	assert.Nil(t, err)
+	res, err := http.Get(authorizationURL.String())
+	assert.Nil(t, err)
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Nil(t, err)
+	verifier := strings.TrimSpace(strings.TrimPrefix(string(body), "Verifier:"))
+
+	_, _, err = config.AccessToken(requestToken, requestSecret, verifier)
+	assert.Nil(t, err)
+}
+
+func TestProvider_Denied(t *testing.T) {
+	p := newTestProvider()
+	server := newTestServer(p)
+	defer server.Close()
+	config := testConfig(server, "https://app.example.com/callback")
+
+	requestToken, _, err := config.RequestToken()
+	assert.Nil(t, err)
+	authorizationURL, err := config.AuthorizationURLWithParams(requestToken, map[string][]string{"deny": {"1"}})
+	assert.Nil(t, err)
+	res, err := noRedirectClient.Get(authorizationURL.String())
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+}
+
+func TestProvider_RejectsConsumers(t *testing.T) {
+	p := newTestProvider()
+	server := newTestServer(p)
+	defer server.Close()
+
+	config := testConfig(server, "https://app.example.com/callback")
+	config.ConsumerSecret = "guess"
+	_, _, err := config.RequestToken()
+	assertProblem(t, err, "signature_invalid")
+
+	config = testConfig(server, "https://app.example.com/callback")
+	config.ConsumerKey = "other_key"
+	_, _, err = config.RequestToken()
+	assertProblem(t, err, "consumer_key_unknown")
+
+	config = testConfig(server, "")
+	_, _, err = config.RequestToken()
+	problem := assertProblem(t, err, "parameter_absent")
+	assert.Equal(t, []string{"oauth_callback"}, problem.ParametersAbsent)
+
+	for _, callback := range []string{"/callback", "javascript:alert(1)", "data:text/html,hi", "ftp://app.example.com/callback"} {
+		config = testConfig(server, callback)
+		_, _, err = config.RequestToken()
+		problem = assertProblem(t, err, "parameter_rejected")
+		assert.Equal(t, []string{"oauth_callback"}, problem.ParametersRejected, callback)
+	}
+
+	res, err := http.Get(server.URL + "/oauth/request_token")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+}
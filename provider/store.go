@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ktnyt/oauth1/internal"
+)
+
+// ErrUnknownToken is returned by token stores when no credentials are
+// stored for a token, or they were exchanged, revoked or have expired.
+var ErrUnknownToken = errors.New("provider: unknown token")
+
+// ErrTokenAuthorized is returned by BindVerifier when a user already
+// authorized the token.
+var ErrTokenAuthorized = errors.New("provider: token already authorized")
+
+// ErrVerifierMismatch is returned by ExchangeRequestToken when the verifier
+// does not match the one bound to the token, or none is bound yet.
+var ErrVerifierMismatch = errors.New("provider: verifier mismatch")
+
+// RequestToken is a set of temporary credentials issued to a consumer.
+type RequestToken struct {
+	ConsumerKey string
+	Token       string
+	TokenSecret string
+
+	// Callback is the oauth_callback of the consumer, "oob" if the
+	// verifier is displayed to the user instead
+	Callback string
+
+	// Verifier and UserID are bound once the user authorized the token
+	Verifier string
+	UserID   string
+
+	Expires time.Time
+}
+
+// AccessToken is a set of token credentials granting a consumer access on
+// behalf of a user.
+type AccessToken struct {
+	ConsumerKey string
+	Token       string
+	TokenSecret string
+	UserID      string
+}
+
+// RequestTokenStore persists temporary credentials from their issue until
+// they are exchanged for token credentials. Implementations must be safe
+// for concurrent use.
+type RequestTokenStore interface {
+	// IssueRequestToken stores newly issued temporary credentials.
+	IssueRequestToken(token *RequestToken) error
+
+	// RequestToken returns the temporary credentials of token or
+	// ErrUnknownToken.
+	RequestToken(token string) (*RequestToken, error)
+
+	// BindVerifier records that userID authorized token and binds
+	// verifier to it. A token is authorized once; later calls return
+	// ErrTokenAuthorized.
+	BindVerifier(token, userID, verifier string) error
+
+	// ExchangeRequestToken removes and returns the temporary credentials
+	// of token if verifier matches the one bound to it, and returns
+	// ErrVerifierMismatch, leaving them stored, otherwise. The comparison
+	// and removal are atomic: of concurrent calls for one token, only one
+	// may succeed; the others return ErrUnknownToken.
+	ExchangeRequestToken(token, verifier string) (*RequestToken, error)
+}
+
+// AccessTokenStore persists token credentials until they are revoked.
+// Implementations must be safe for concurrent use.
+type AccessTokenStore interface {
+	// IssueAccessToken stores newly issued token credentials.
+	IssueAccessToken(token *AccessToken) error
+
+	// AccessToken returns the token credentials of token or
+	// ErrUnknownToken.
+	AccessToken(token string) (*AccessToken, error)
+
+	// RevokeAccessToken removes the token credentials of token.
+	RevokeAccessToken(token string) error
+}
+
+// MemoryTokenStore is a RequestTokenStore and AccessTokenStore keeping
+// credentials in memory, for tests and single instance providers. Expired
+// temporary credentials are unknown; call Prune periodically or start the
+// janitor to free them.
+// A MemoryTokenStore is safe for concurrent use.
+type MemoryTokenStore struct {
+	mu       sync.Mutex
+	requests map[string]RequestToken
+	access   map[string]AccessToken
+	now      func() time.Time
+	janitor  internal.Janitor
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		requests: make(map[string]RequestToken),
+		access:   make(map[string]AccessToken),
+	}
+}
+
+// IssueRequestToken stores token.
+func (s *MemoryTokenStore) IssueRequestToken(token *RequestToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[token.Token] = *token
+	return nil
+}
+
+// RequestToken returns a copy of the temporary credentials of token.
+func (s *MemoryTokenStore) RequestToken(token string) (*RequestToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.requestToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// BindVerifier binds userID and verifier to token, unless already bound.
+func (s *MemoryTokenStore) BindVerifier(token, userID, verifier string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.requestToken(token)
+	if err != nil {
+		return err
+	}
+	if t.Verifier != "" {
+		return ErrTokenAuthorized
+	}
+	t.UserID, t.Verifier = userID, verifier
+	s.requests[token] = t
+	return nil
+}
+
+// ExchangeRequestToken removes and returns the temporary credentials of
+// token if verifier matches the one bound to it.
+func (s *MemoryTokenStore) ExchangeRequestToken(token, verifier string) (*RequestToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.requestToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if t.Verifier == "" || subtle.ConstantTimeCompare([]byte(t.Verifier), []byte(verifier)) != 1 {
+		return nil, ErrVerifierMismatch
+	}
+	delete(s.requests, token)
+	return &t, nil
+}
+
+// IssueAccessToken stores token.
+func (s *MemoryTokenStore) IssueAccessToken(token *AccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.access[token.Token] = *token
+	return nil
+}
+
+// AccessToken returns a copy of the token credentials of token.
+func (s *MemoryTokenStore) AccessToken(token string) (*AccessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.access[token]
+	if !ok {
+		return nil, ErrUnknownToken
+	}
+	return &t, nil
+}
+
+// RevokeAccessToken removes the token credentials of token.
+func (s *MemoryTokenStore) RevokeAccessToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.access, token)
+	return nil
+}
+
+// Prune removes expired temporary credentials.
+func (s *MemoryTokenStore) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock()
+	for token, t := range s.requests {
+		if !now.Before(t.Expires) {
+			delete(s.requests, token)
+		}
+	}
+}
+
+// StartJanitor prunes expired temporary credentials every interval, or
+// every minute if interval is not positive, in a background goroutine until
+// Stop is called.
+func (s *MemoryTokenStore) StartJanitor(interval time.Duration) {
+	s.janitor.Start(interval, s.Prune)
+}
+
+// Stop stops the janitor goroutine, if running.
+func (s *MemoryTokenStore) Stop() {
+	s.janitor.Stop()
+}
+
+// requestToken returns the unexpired temporary credentials of token.
+func (s *MemoryTokenStore) requestToken(token string) (RequestToken, error) {
+	t, ok := s.requests[token]
+	if !ok || !s.clock().Before(t.Expires) {
+		return RequestToken{}, ErrUnknownToken
+	}
+	return t, nil
+}
+
+func (s *MemoryTokenStore) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTokenStore_RequestTokens(t *testing.T) {
+	now := time.Unix(1318467427, 0)
+	store := NewMemoryTokenStore()
+	store.now = func() time.Time { return now }
+	assert.Nil(t, store.IssueRequestToken(&RequestToken{Token: "request", TokenSecret: "secret", Expires: now.Add(time.Minute)}))
+
+	assert.Nil(t, store.BindVerifier("request", "alice", "verifier"))
+	token, err := store.RequestToken("request")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", token.UserID)
+	assert.Equal(t, "verifier", token.Verifier)
+
+	// tokens are authorized once
+	assert.Equal(t, ErrTokenAuthorized, store.BindVerifier("request", "mallory", "other"))
+
+	// changes to returned credentials are not stored
+	token.Verifier = "guess"
+	_, err = store.ExchangeRequestToken("request", "guess")
+	assert.Equal(t, ErrVerifierMismatch, err)
+	token, err = store.ExchangeRequestToken("request", "verifier")
+	assert.Nil(t, err)
+	assert.Equal(t, "verifier", token.Verifier)
+	assert.Equal(t, "alice", token.UserID)
+
+	_, err = store.ExchangeRequestToken("request", "verifier")
+	assert.Equal(t, ErrUnknownToken, err)
+	assert.Equal(t, ErrUnknownToken, store.BindVerifier("request", "alice", "verifier"))
+}
+
+func TestMemoryTokenStore_ConcurrentAuthorization(t *testing.T) {
+	store := NewMemoryTokenStore()
+	assert.Nil(t, store.IssueRequestToken(&RequestToken{Token: "request", Expires: time.Now().Add(time.Minute)}))
+	_, err := store.ExchangeRequestToken("request", "")
+	assert.Equal(t, ErrVerifierMismatch, err)
+
+	// users race to authorize the token while it is being exchanged
+	users := []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi"}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var bound []string
+	exchanged := make(map[string]*RequestToken)
+	for _, user := range users {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			if store.BindVerifier("request", user, user+"_verifier") != nil {
+				return
+			}
+			mu.Lock()
+			bound = append(bound, user)
+			mu.Unlock()
+		}(user)
+		for _, other := range users {
+			wg.Add(1)
+			go func(verifier string) {
+				defer wg.Done()
+				token, err := store.ExchangeRequestToken("request", verifier)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				exchanged[verifier] = token
+				mu.Unlock()
+			}(other + "_verifier")
+		}
+	}
+	wg.Wait()
+
+	if assert.Len(t, bound, 1) {
+		user := bound[0]
+		// the token was exchanged at most once, for the user bound to it
+		assert.True(t, len(exchanged) <= 1)
+		for verifier, token := range exchanged {
+			assert.Equal(t, user+"_verifier", verifier)
+			assert.Equal(t, user, token.UserID)
+		}
+	}
+}
+
+func TestMemoryTokenStore_Expiry(t *testing.T) {
+	now := time.Unix(1318467427, 0)
+	store := NewMemoryTokenStore()
+	store.now = func() time.Time { return now }
+	assert.Nil(t, store.IssueRequestToken(&RequestToken{Token: "request", Expires: now.Add(time.Minute)}))
+
+	now = now.Add(time.Minute)
+	_, err := store.RequestToken("request")
+	assert.Equal(t, ErrUnknownToken, err)
+	store.Prune()
+	assert.Empty(t, store.requests)
+}
+
+func TestMemoryTokenStore_Janitor(t *testing.T) {
+	store := NewMemoryTokenStore()
+	assert.Nil(t, store.IssueRequestToken(&RequestToken{Token: "request", Expires: time.Now()}))
+	store.StartJanitor(time.Millisecond)
+	defer store.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		store.mu.Lock()
+		n := len(store.requests)
+		store.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	store.mu.Lock()
+	assert.Empty(t, store.requests)
+	store.mu.Unlock()
+	store.Stop()
+}
+
+func TestMemoryTokenStore_AccessTokens(t *testing.T) {
+	store := NewMemoryTokenStore()
+	assert.Nil(t, store.IssueAccessToken(&AccessToken{Token: "access", TokenSecret: "secret", UserID: "alice"}))
+	token, err := store.AccessToken("access")
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", token.UserID)
+
+	assert.Nil(t, store.RevokeAccessToken("access"))
+	_, err = store.AccessToken("access")
+	assert.Equal(t, ErrUnknownToken, err)
+}
@@ -0,0 +1,25 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+)
+
+// maxRedirects is the number of redirects followed by ResignRedirects, the
+// same as by net/http's default policy.
+const maxRedirects = 10
+
+// ResignRedirects returns a CheckRedirect function for an http.Client sending
+// requests signed with SignRequest. It signs each redirected request anew
+// with the Config's consumer credentials and token, as a redirect to another
+// path or host, such as Tumblr's, invalidates the original signature. The
+// clients returned by Client and the other constructors need no such
+// function: their Transport signs every request, redirects included.
+func (c *Config) ResignRedirects(token *Token) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("oauth1: Stopped after 10 redirects")
+		}
+		return c.SignRequest(req, token)
+	}
+}
@@ -0,0 +1,76 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRedirectingServers returns an API server verifying that requests carry
+// a signature for its URL and a server redirecting every request to it.
+func newRedirectingServers(t *testing.T, config *Config, token *Token) (api, redirector *httptest.Server) {
+	api = newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, token.Token, params["oauth_token"])
+		signed, err := http.NewRequest(req.Method, "http://"+req.Host+req.URL.RequestURI(), nil)
+		assert.Nil(t, err)
+		header, err := config.signing().authorizationHeader(signed, token.TokenSecret,
+			map[string][]string{"oauth_token": {token.Token}},
+			stamp{params["oauth_nonce"], time.Unix(mustParseInt(t, params["oauth_timestamp"]), 0)})
+		assert.Nil(t, err)
+		assert.Equal(t, parseOAuthParamsOrFail(t, header)["oauth_signature"], params["oauth_signature"])
+	})
+	redirector = newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, api.URL+"/v2"+req.URL.Path, http.StatusFound)
+	})
+	return api, redirector
+}
+
+func TestConfigResignRedirects(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	token := &Token{Token: "token", TokenSecret: "token_secret"}
+	api, redirector := newRedirectingServers(t, config, token)
+	defer api.Close()
+	defer redirector.Close()
+
+	req, err := http.NewRequest("GET", redirector.URL+"/blog/posts", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, config.SignRequest(req, token))
+	client := &http.Client{CheckRedirect: config.ResignRedirects(token)}
+	res, err := client.Do(req)
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.True(t, strings.HasPrefix(res.Request.URL.String(), api.URL+"/v2/blog/posts"))
+	}
+}
+
+func TestConfigResignRedirects_Limit(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key"}
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, req.URL.Path, http.StatusFound)
+	})
+	defer server.Close()
+
+	client := &http.Client{CheckRedirect: config.ResignRedirects(nil)}
+	_, err := client.Get(server.URL)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "oauth1: Stopped after 10 redirects")
+	}
+}
+
+func TestConfigClient_SignsRedirects(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	token := &Token{Token: "token", TokenSecret: "token_secret"}
+	api, redirector := newRedirectingServers(t, config, token)
+	defer api.Close()
+	defer redirector.Close()
+
+	res, err := config.Client(NoContext, token.Token, token.TokenSecret).Get(redirector.URL + "/blog/posts")
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
@@ -41,11 +41,11 @@ func TestTwitterRequestTokenAuthHeader(t *testing.T) {
 	data, err := prepareParams(req, config.ConsumerKey)
 	assert.Nil(t, err)
 	data.Add("oauth_callback", config.CallbackURL)
-	signer := Signer{expectedNonce, time.Unix(unixTimestamp, 0)}
-	signature, err := signer.Sign(config.ConsumerSecret, "", req, data)
+	st := stamp{expectedNonce, time.Unix(unixTimestamp, 0)}
+	signature, err := config.signing().sign(req, "", data, st)
 	assert.Nil(t, err)
 	data.Add("oauth_signature", signature)
-	req.Header.Add("Authorization", formatOAuthHeader(data))
+	req.Header.Add("Authorization", FormatOAuthHeader("", data))
 	// assert the request for a request token is signed and has an oauth_callback
 	assert.Nil(t, err)
 	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
@@ -86,11 +86,11 @@ func TestTwitterAccessTokenAuthHeader(t *testing.T) {
 	assert.Nil(t, err)
 	data.Add("oauth_token", expectedRequestToken)
 	data.Add("oauth_verifier", expectedVerifier)
-	signer := Signer{expectedNonce, time.Unix(unixTimestamp, 0)}
-	signature, err := signer.Sign(config.ConsumerSecret, requestTokenSecret, req, data)
+	st := stamp{expectedNonce, time.Unix(unixTimestamp, 0)}
+	signature, err := config.signing().sign(req, requestTokenSecret, data, st)
 	assert.Nil(t, err)
 	data.Add("oauth_signature", signature)
-	req.Header.Add("Authorization", formatOAuthHeader(data))
+	req.Header.Add("Authorization", FormatOAuthHeader("", data))
 	// assert the request for an access token is signed and has an oauth_token and verifier
 	assert.Nil(t, err)
 	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
@@ -137,7 +137,7 @@ func TestTwitterParameterString(t *testing.T) {
 	params.Add("oauth_token", expectedTwitterOAuthToken)
 	// assert that the parameter string matches the reference
 	expectedParameterString := "include_entities=true&oauth_consumer_key=xvz1evFS4wEEPTGEFPHBog&oauth_nonce=kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg&oauth_signature_method=HMAC-SHA1&oauth_timestamp=1318622958&oauth_token=370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb&oauth_version=1.0&status=Hello%20Ladies%20%2B%20Gentlemen%2C%20a%20signed%20OAuth%20request%21"
-	assert.Equal(t, expectedParameterString, normalizeSpace(params.Encode()))
+	assert.Equal(t, expectedParameterString, encodeParams(params))
 }
 
 func TestTwitterSignatureBase(t *testing.T) {
@@ -150,8 +150,8 @@ func TestTwitterSignatureBase(t *testing.T) {
 	params, err := prepareParams(req, twitterConfig.ConsumerKey)
 	assert.Nil(t, err)
 	params.Add("oauth_token", expectedTwitterOAuthToken)
-	signer := Signer{expectedNonce, time.Unix(unixTimestampOfRequest, 0)}
-	signatureBase := signer.Base(req, params)
+	st := stamp{expectedNonce, time.Unix(unixTimestampOfRequest, 0)}
+	signatureBase := st.base(req, params)
 	// assert that the signature base string matches the reference
 	// checks that method is uppercased, url is encoded, parameter string is added, all joined by &
 	expectedSignatureBase := "POST&https%3A%2F%2Fapi.twitter.com%2F1%2Fstatuses%2Fupdate.json&include_entities%3Dtrue%26oauth_consumer_key%3Dxvz1evFS4wEEPTGEFPHBog%26oauth_nonce%3DkYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg%26oauth_signature_method%3DHMAC-SHA1%26oauth_timestamp%3D1318622958%26oauth_token%3D370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb%26oauth_version%3D1.0%26status%3DHello%2520Ladies%2520%252B%2520Gentlemen%252C%2520a%2520signed%2520OAuth%2520request%2521"
@@ -173,11 +173,11 @@ func TestTwitterRequestAuthHeader(t *testing.T) {
 	data, err := prepareParams(req, twitterConfig.ConsumerKey)
 	assert.Nil(t, err)
 	data.Add("oauth_token", expectedTwitterOAuthToken)
-	signer := Signer{expectedNonce, time.Unix(unixTimestampOfRequest, 0)}
-	signature, err := signer.Sign(twitterConfig.ConsumerSecret, oauthTokenSecret, req, data)
+	st := stamp{expectedNonce, time.Unix(unixTimestampOfRequest, 0)}
+	signature, err := twitterConfig.signing().sign(req, oauthTokenSecret, data, st)
 	assert.Nil(t, err)
 	data.Add("oauth_signature", signature)
-	req.Header.Set("Authorization", formatOAuthHeader(data))
+	req.Header.Set("Authorization", FormatOAuthHeader("", data))
 	// assert that request is signed and has an access token token
 	assert.Nil(t, err)
 	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
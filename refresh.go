@@ -0,0 +1,88 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// expiryDelta is how long before its Expiry a Token is refreshed, so that
+// requests in flight do not carry an expired token.
+const expiryDelta = time.Minute
+
+// ErrNoSessionHandle is returned when refreshing a Token without a
+// SessionHandle.
+var ErrNoSessionHandle = errors.New("oauth1: Token has no session handle to refresh")
+
+// RefreshToken obtains a new access token for an expired one by POSTing a
+// request (with oauth_token and oauth_session_handle in the auth header)
+// signed with the expired token's secret to the Endpoint AccessTokenURL, per
+// the ScalableOAuth session extension used by Yahoo. The new Token keeps
+// sessionHandle unless the provider issues another one.
+func (c *Config) RefreshToken(token, secret, sessionHandle string) (*Token, error) {
+	return c.RefreshTokenContext(c.context(), token, secret, sessionHandle)
+}
+
+// RefreshTokenContext is like RefreshToken but makes the request with ctx.
+func (c *Config) RefreshTokenContext(ctx context.Context, token, secret, sessionHandle string) (*Token, error) {
+	if sessionHandle == "" {
+		return nil, ErrNoSessionHandle
+	}
+	oauthParams := make(url.Values)
+	oauthParams.Add("oauth_token", token)
+	oauthParams.Add("oauth_session_handle", sessionHandle)
+	values, err := c.tokenRequest(ctx, accessTokenURL, secret, oauthParams, nil)
+	if err != nil {
+		return nil, err
+	}
+	refreshed, err := c.tokenFromValues(values)
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.SessionHandle == "" {
+		refreshed.SessionHandle = sessionHandle
+	}
+	atomic.AddInt64(&stats.tokenRefreshes, 1)
+	return refreshed, nil
+}
+
+// RefreshingTokenSource returns a TokenSource which returns token until it
+// is about to expire and then refreshes it with RefreshToken. Tokens without
+// an Expiry are never refreshed. The TokenSource is a ContextTokenSource, so
+// a Transport refreshes with the context of the request it signs.
+func (c *Config) RefreshingTokenSource(token *Token) TokenSource {
+	return &refreshingTokenSource{config: c, token: token}
+}
+
+// refreshingTokenSource is a TokenSource refreshing expiring Tokens.
+type refreshingTokenSource struct {
+	config *Config
+
+	mu    sync.Mutex
+	token *Token
+}
+
+func (s *refreshingTokenSource) Token() (*Token, error) {
+	return s.TokenContext(s.config.context())
+}
+
+// TokenContext is like Token but refreshes with ctx.
+func (s *refreshingTokenSource) TokenContext(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil {
+		return nil, errors.New("oauth1: Token is nil")
+	}
+	if s.token.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(s.token.Expiry) {
+		return s.token, nil
+	}
+	token, err := s.config.RefreshTokenContext(ctx, s.token.Token, s.token.TokenSecret, s.token.SessionHandle)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
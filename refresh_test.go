@@ -0,0 +1,116 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRefreshServer returns a server refreshing tokens with session_handle,
+// issuing access_token_N tokens valid for an hour.
+func newRefreshServer(t *testing.T) (*Config, *int) {
+	refreshes := new(int)
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "session_handle", params["oauth_session_handle"])
+		*refreshes++
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte(url.Values{
+			"oauth_token":        {"access_token_" + strconv.Itoa(*refreshes)},
+			"oauth_token_secret": {"access_secret"},
+			"oauth_expires_in":   {"3600"},
+		}.Encode()))
+	})
+	t.Cleanup(server.Close)
+	return &Config{ConsumerKey: "consumer_key", Endpoint: Endpoint{AccessTokenURL: server.URL}}, refreshes
+}
+
+func TestConfigRefreshToken(t *testing.T) {
+	config, _ := newRefreshServer(t)
+	before := ReadStats().TokenRefreshes
+
+	token, err := config.RefreshToken("expired_token", "expired_secret", "session_handle")
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token_1", token.Token)
+	assert.Equal(t, "access_secret", token.TokenSecret)
+	assert.Equal(t, "session_handle", token.SessionHandle)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+	assert.Equal(t, before+1, ReadStats().TokenRefreshes)
+}
+
+func TestConfigRefreshToken_NoSessionHandle(t *testing.T) {
+	config := &Config{}
+	_, err := config.RefreshToken("token", "secret", "")
+	assert.Equal(t, ErrNoSessionHandle, err)
+}
+
+func TestRefreshingTokenSource(t *testing.T) {
+	config, refreshes := newRefreshServer(t)
+	src := config.RefreshingTokenSource(&Token{
+		Token:         "access_token_0",
+		TokenSecret:   "access_secret",
+		Expiry:        time.Now().Add(30 * time.Second),
+		SessionHandle: "session_handle",
+	})
+
+	// expiring within expiryDelta, so refreshed once
+	for i := 0; i < 3; i++ {
+		token, err := src.Token()
+		assert.Nil(t, err)
+		assert.Equal(t, "access_token_1", token.Token)
+	}
+	assert.Equal(t, 1, *refreshes)
+}
+
+func TestRefreshingTokenSource_RequestContext(t *testing.T) {
+	config, refreshes := newRefreshServer(t)
+	api := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("expected the request not to be sent")
+	})
+	defer api.Close()
+	src := config.RefreshingTokenSource(&Token{
+		Token:         "access_token_0",
+		TokenSecret:   "access_secret",
+		Expiry:        time.Now().Add(30 * time.Second),
+		SessionHandle: "session_handle",
+	})
+
+	// cancelling the request cancels the refresh it triggers
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequest("GET", api.URL, nil)
+	assert.Nil(t, err)
+	_, err = config.TokenSourceClient(NoContext, src).Do(req.WithContext(ctx))
+	assert.Error(t, err)
+	assert.Equal(t, 0, *refreshes)
+
+	_, err = config.RefreshTokenContext(ctx, "access_token_0", "access_secret", "session_handle")
+	assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+	assert.Equal(t, 0, *refreshes)
+}
+
+func TestRefreshingTokenSource_NoExpiry(t *testing.T) {
+	config, refreshes := newRefreshServer(t)
+	token := &Token{Token: "access_token", TokenSecret: "access_secret", SessionHandle: "session_handle"}
+	got, err := config.RefreshingTokenSource(token).Token()
+	assert.Nil(t, err)
+	assert.Equal(t, token, got)
+	assert.Equal(t, 0, *refreshes)
+}
+
+func TestTokenFromValues_ExpiresIn(t *testing.T) {
+	_, err := (&Config{}).tokenFromValues(url.Values{
+		"oauth_token":        {"token"},
+		"oauth_token_secret": {"secret"},
+		"oauth_expires_in":   {"soon"},
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, `oauth1: Invalid oauth_expires_in "soon"`, err.Error())
+	}
+}
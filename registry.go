@@ -0,0 +1,51 @@
+package oauth1
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Config)
+)
+
+// Register makes a copy of config available by name to Lookup, replacing
+// any Config previously registered under that name. Applications working
+// with several providers can register one Config per provider at startup
+// instead of passing Config values through every layer. Register, Lookup,
+// Unregister and Registered are safe for concurrent use.
+func Register(name string, config *Config) {
+	clone := config.Clone()
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = clone
+}
+
+// Lookup returns the Config registered under name. The Config is shared
+// between all callers and must not be modified; Clone it to derive a variant.
+func Lookup(name string) (*Config, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	config, ok := registry[name]
+	return config, ok
+}
+
+// Unregister removes the Config registered under name, if any.
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, name)
+}
+
+// Registered returns the sorted names of the registered Configs.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
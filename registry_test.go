@@ -0,0 +1,52 @@
+package oauth1
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	defer Unregister("twitter")
+	defer Unregister("tumblr")
+
+	config := &Config{ConsumerKey: "twitter_key"}
+	Register("twitter", config)
+	Register("tumblr", &Config{ConsumerKey: "tumblr_key"})
+
+	// later changes to the registered value do not leak into the registry
+	config.ConsumerKey = "modified"
+	registered, ok := Lookup("twitter")
+	assert.True(t, ok)
+	assert.Equal(t, "twitter_key", registered.ConsumerKey)
+	assert.Equal(t, []string{"tumblr", "twitter"}, Registered())
+
+	Register("twitter", &Config{ConsumerKey: "replaced_key"})
+	registered, ok = Lookup("twitter")
+	assert.True(t, ok)
+	assert.Equal(t, "replaced_key", registered.ConsumerKey)
+
+	Unregister("twitter")
+	_, ok = Lookup("twitter")
+	assert.False(t, ok)
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("provider%d", i)
+		defer Unregister(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Register(name, &Config{ConsumerKey: name})
+			config, ok := Lookup(name)
+			assert.True(t, ok)
+			assert.Equal(t, name, config.ConsumerKey)
+			Registered()
+		}()
+	}
+	wg.Wait()
+}
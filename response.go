@@ -0,0 +1,78 @@
+package oauth1
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ResponseParser decodes the body of a token endpoint response of the given
+// media type (e.g. "application/json") into its parameters.
+type ResponseParser func(mediaType string, body []byte) (url.Values, error)
+
+// ParseResponse is the default ResponseParser. It decodes application/json
+// bodies, and bodies of other types which hold a JSON object, from a JSON
+// object with string, number or boolean members, and any other body as
+// application/x-www-form-urlencoded.
+func ParseResponse(mediaType string, body []byte) (url.Values, error) {
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || isJSONObject(body) {
+		return parseJSONResponse(body)
+	}
+	return url.ParseQuery(string(body))
+}
+
+// isJSONObject reports whether body looks like a JSON object, for providers
+// which send JSON labelled as form or text.
+func isJSONObject(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) > 1 && body[0] == '{' && body[len(body)-1] == '}'
+}
+
+func parseJSONResponse(body []byte) (url.Values, error) {
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	values := make(url.Values)
+	for key, value := range object {
+		switch value := value.(type) {
+		case string:
+			values.Set(key, value)
+		case bool:
+			values.Set(key, strconv.FormatBool(value))
+		case json.Number:
+			values.Set(key, value.String())
+		}
+	}
+	return values, nil
+}
+
+// responseMediaType returns the media type used to decode res. Responses
+// without a specific Content-Type (missing or text/*, which providers often
+// send for form-encoded bodies) are decoded as the type requested by accept.
+func responseMediaType(res *http.Response, accept string) string {
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err == nil && !strings.HasPrefix(mediaType, "text/") {
+		return mediaType
+	}
+	if accept != "" {
+		accepted, _, err := mime.ParseMediaType(strings.Split(accept, ",")[0])
+		if err == nil {
+			return accepted
+		}
+	}
+	return "application/x-www-form-urlencoded"
+}
+
+func (c *Config) responseParser() ResponseParser {
+	if c.ResponseParser != nil {
+		return c.ResponseParser
+	}
+	return ParseResponse
+}
@@ -0,0 +1,107 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResponse(t *testing.T) {
+	values, err := ParseResponse("application/x-www-form-urlencoded", []byte("oauth_token=token&oauth_token_secret=secret"))
+	assert.Nil(t, err)
+	assert.Equal(t, "token", values.Get("oauth_token"))
+	assert.Equal(t, "secret", values.Get("oauth_token_secret"))
+
+	values, err = ParseResponse("application/json", []byte(`{"oauth_token":"token","oauth_token_secret":"secret","oauth_callback_confirmed":true,"user_id":12345}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "token", values.Get("oauth_token"))
+	assert.Equal(t, "secret", values.Get("oauth_token_secret"))
+	assert.Equal(t, "true", values.Get("oauth_callback_confirmed"))
+	assert.Equal(t, "12345", values.Get("user_id"))
+
+	_, err = ParseResponse("application/json", []byte("oauth_token=token"))
+	assert.NotNil(t, err)
+}
+
+func TestParseResponse_SniffsJSON(t *testing.T) {
+	values, err := ParseResponse("application/x-www-form-urlencoded", []byte(" {\"oauth_token\":\"token\",\"oauth_token_secret\":\"secret\"}\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "token", values.Get("oauth_token"))
+	assert.Equal(t, "secret", values.Get("oauth_token_secret"))
+}
+
+func TestConfigAccessToken_JSONLabelledAsText(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`{"oauth_token":"access_token","oauth_token_secret":"access_secret"}`))
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{AccessTokenURL: server.URL}}
+	accessToken, accessSecret, err := config.AccessToken("request_token", "request_secret", expectedVerifier)
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", accessToken)
+	assert.Equal(t, "access_secret", accessSecret)
+}
+
+func TestResponseMediaType(t *testing.T) {
+	cases := []struct {
+		contentType string
+		accept      string
+		expected    string
+	}{
+		{"application/json; charset=utf-8", "", "application/json"},
+		{"application/x-www-form-urlencoded", "application/json", "application/x-www-form-urlencoded"},
+		{"text/html; charset=utf-8", "application/json", "application/json"},
+		{"", "application/json, */*;q=0.1", "application/json"},
+		{"text/plain", "", "application/x-www-form-urlencoded"},
+	}
+	for _, c := range cases {
+		res := &http.Response{Header: http.Header{}}
+		if c.contentType != "" {
+			res.Header.Set("Content-Type", c.contentType)
+		}
+		assert.Equal(t, c.expected, responseMediaType(res, c.accept))
+	}
+}
+
+func TestConfigRequestToken_AcceptJSON(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(`{"oauth_token":"request_token","oauth_token_secret":"request_secret","oauth_callback_confirmed":"true"}`))
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: server.URL},
+		Accept:   "application/json",
+	}
+	requestToken, requestSecret, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+	assert.Equal(t, "request_secret", requestSecret)
+}
+
+func TestConfigAccessToken_CustomResponseParser(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte("<token/>"))
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{AccessTokenURL: server.URL},
+		ResponseParser: func(mediaType string, body []byte) (url.Values, error) {
+			assert.Equal(t, "application/xml", mediaType)
+			assert.Equal(t, "<token/>", string(body))
+			return url.Values{"oauth_token": {"access_token"}, "oauth_token_secret": {"access_secret"}}, nil
+		},
+	}
+	accessToken, accessSecret, err := config.AccessToken("request_token", "request_secret", "verifier")
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", accessToken)
+	assert.Equal(t, "access_secret", accessSecret)
+}
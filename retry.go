@@ -0,0 +1,103 @@
+package oauth1
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy retries token endpoint requests which fail with a network
+// error, a 5xx status or 429 Too Many Requests, waiting with exponential
+// backoff between attempts. Each attempt is signed with a fresh nonce and
+// timestamp.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry, doubled for each
+	// subsequent one. Defaults to DefaultMinBackoff
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts, including waits asked for
+	// by a Retry-After header. Defaults to DefaultMaxBackoff
+	MaxBackoff time.Duration
+}
+
+// Default backoff bounds of a RetryPolicy.
+const (
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// do calls attempt until it succeeds, fails with an error which should not
+// be retried or the policy's attempts are exhausted. A nil policy makes a
+// single attempt. Waiting stops when ctx, if not nil, is done.
+func (p *RetryPolicy) do(ctx context.Context, attempt func() (*http.Response, error)) (*http.Response, error) {
+	for n := 1; ; n++ {
+		res, err := attempt()
+		if permanent, ok := err.(permanentError); ok {
+			return nil, permanent.error
+		}
+		if p == nil || n >= p.MaxAttempts || !retryable(res, err) {
+			return res, err
+		}
+		wait := p.backoff(n, res)
+		if res != nil {
+			res.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// permanentError wraps errors of an attempt which retrying cannot fix, such
+// as failures to build a request.
+type permanentError struct {
+	error
+}
+
+// retryable reports whether a token request with the given outcome should
+// be retried.
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+}
+
+// backoff returns the wait after the nth attempt.
+func (p *RetryPolicy) backoff(n int, res *http.Response) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = DefaultMinBackoff
+	}
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	wait := min << uint(n-1)
+	if res != nil {
+		if seconds, err := time.ParseDuration(res.Header.Get("Retry-After") + "s"); err == nil && seconds > 0 {
+			wait = seconds
+		}
+	}
+	if wait > max || wait <= 0 {
+		wait = max
+	}
+	return wait
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if ctx == nil {
+		time.Sleep(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,122 @@
+package oauth1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigRequestToken_Retry(t *testing.T) {
+	var attempts int
+	var nonces []string
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		nonces = append(nonces, parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))["oauth_nonce"])
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+			w.Write([]byte("oauth_token=request_token&oauth_token_secret=request_secret&oauth_callback_confirmed=true"))
+		}
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: server.URL},
+		Retry:    &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond},
+	}
+	requestToken, _, err := config.RequestToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "request_token", requestToken)
+	assert.Equal(t, 3, attempts)
+	assert.NotEqual(t, nonces[0], nonces[1])
+}
+
+func TestConfigRequestToken_RetryExhausted(t *testing.T) {
+	var attempts int
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: server.URL},
+		Retry:    &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond},
+	}
+	_, _, err := config.RequestToken()
+	assert.Error(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestConfigRequestToken_NoRetryOnClientError(t *testing.T) {
+	var attempts int
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: server.URL},
+		Retry:    &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond},
+	}
+	_, _, err := config.RequestToken()
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestConfigRequestToken_RetryNetworkError(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: newClosedServerURL()},
+		Retry:    &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond},
+	}
+	_, _, err := config.RequestToken()
+	assert.Error(t, err)
+}
+
+func TestConfigRequestToken_RetryBuildErrorNotRetried(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{RequestTokenURL: "%gh&%ij"},
+		Retry:    &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour},
+	}
+	_, _, err := config.RequestToken()
+	assert.Error(t, err)
+}
+
+func TestConfigRequestToken_RetryCanceled(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	config := &Config{
+		Context:  ctx,
+		Endpoint: Endpoint{RequestTokenURL: server.URL},
+		Retry:    &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour},
+	}
+	_, _, err := config.RequestToken()
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.backoff(1, nil))
+	assert.Equal(t, 2*time.Second, p.backoff(2, nil))
+	assert.Equal(t, 4*time.Second, p.backoff(3, nil))
+	assert.Equal(t, 5*time.Second, p.backoff(4, nil))
+
+	res := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	assert.Equal(t, 3*time.Second, p.backoff(1, res))
+	res.Header.Set("Retry-After", "120")
+	assert.Equal(t, 5*time.Second, p.backoff(1, res))
+	assert.Equal(t, DefaultMinBackoff, (&RetryPolicy{}).backoff(1, nil))
+}
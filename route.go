@@ -0,0 +1,58 @@
+package oauth1
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Route matches requests by method, host and path and selects how a
+// Transport signs them. Useful when one API host mixes OAuth1-protected
+// endpoints with public ones.
+type Route struct {
+	// Method to match, any method if empty
+	Method string
+
+	// Host to match, any host if empty
+	Host string
+
+	// Path to match, any path if empty. As with http.ServeMux, a Path
+	// ending in a slash matches the whole subtree rooted at it.
+	Path string
+
+	// Unsigned requests are sent without an Authorization header
+	Unsigned bool
+
+	// Source supplies the Token for the route, overriding the Transport's
+	Source TokenSource
+
+	// Signer computes the signatures of the route, overriding the
+	// Transport's, e.g. for endpoints requiring another signature method
+	Signer Signer
+}
+
+// Match reports whether the route applies to req.
+func (r *Route) Match(req *http.Request) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, req.Method) {
+		return false
+	}
+	if r.Host != "" && !strings.EqualFold(r.Host, req.URL.Host) {
+		return false
+	}
+	if r.Path == "" {
+		return true
+	}
+	if strings.HasSuffix(r.Path, "/") {
+		return strings.HasPrefix(req.URL.Path, r.Path)
+	}
+	return req.URL.Path == r.Path
+}
+
+// route returns the first of routes matching req, or nil.
+func route(routes []Route, req *http.Request) *Route {
+	for i := range routes {
+		if routes[i].Match(req) {
+			return &routes[i]
+		}
+	}
+	return nil
+}
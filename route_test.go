@@ -0,0 +1,104 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteMatch(t *testing.T) {
+	cases := []struct {
+		route    Route
+		method   string
+		url      string
+		expected bool
+	}{
+		{Route{}, "GET", "https://api.example.com/anything", true},
+		{Route{Method: "post"}, "POST", "https://api.example.com/", true},
+		{Route{Method: "POST"}, "GET", "https://api.example.com/", false},
+		{Route{Host: "api.example.com"}, "GET", "https://API.example.com/", true},
+		{Route{Host: "api.example.com"}, "GET", "https://cdn.example.com/", false},
+		{Route{Path: "/public/"}, "GET", "https://api.example.com/public/feed", true},
+		{Route{Path: "/public/"}, "GET", "https://api.example.com/private/feed", false},
+		{Route{Path: "/status"}, "GET", "https://api.example.com/status", true},
+		{Route{Path: "/status"}, "GET", "https://api.example.com/status/1", false},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.url, nil)
+		assert.Equal(t, c.expected, c.route.Match(req), "%+v %s %s", c.route, c.method, c.url)
+	}
+}
+
+func TestTransport_Routes(t *testing.T) {
+	authorization := map[string]string{}
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		authorization[req.URL.Path] = req.Header.Get("Authorization")
+	})
+	defer server.Close()
+
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		Routes: []Route{
+			{Path: "/public/", Unsigned: true},
+			{Path: "/admin/", Source: &rotatingTokenSource{tokens: []*Token{{Token: "admin_token", TokenSecret: "admin_secret"}}}},
+		},
+	}
+	client := config.Client(NoContext, "user_token", "user_secret")
+	for _, path := range []string{"/public/feed", "/admin/users", "/home"} {
+		_, err := client.Get(server.URL + path)
+		assert.Nil(t, err)
+	}
+	assert.Empty(t, authorization["/public/feed"])
+	assert.Equal(t, "admin_token", parseOAuthParamsOrFail(t, authorization["/admin/users"])["oauth_token"])
+	assert.Equal(t, "user_token", parseOAuthParamsOrFail(t, authorization["/home"])["oauth_token"])
+}
+
+func TestTransport_RoutesSignOnlyListed(t *testing.T) {
+	authorization := map[string]string{}
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		authorization[req.URL.Path] = req.Header.Get("Authorization")
+	})
+	defer server.Close()
+
+	config := &Config{
+		ConsumerKey: "consumer_key",
+		Routes: []Route{
+			{Method: "POST", Path: "/statuses/update"},
+			{Unsigned: true},
+		},
+	}
+	client := config.Client(NoContext, "user_token", "user_secret")
+	_, err := client.Post(server.URL+"/statuses/update", "text/plain", nil)
+	assert.Nil(t, err)
+	_, err = client.Get(server.URL + "/statuses/show")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, authorization["/statuses/update"])
+	assert.Empty(t, authorization["/statuses/show"])
+}
+
+func TestTransport_RouteSigner(t *testing.T) {
+	v := newTestVerifier()
+	methods := map[string]string{}
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params, err := v.Verify(req)
+		assert.Nil(t, err, req.URL.Path)
+		methods[req.URL.Path] = params.Get("oauth_signature_method")
+	})
+	defer server.Close()
+
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer secret",
+		Routes:         []Route{{Path: "/v2/", Signer: HMACSHA256Signer{}}},
+	}
+	client := config.Client(NoContext, "token", "token&secret")
+	for _, path := range []string{"/v2/feed", "/v1/feed"} {
+		_, err := client.Get(server.URL + path)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, "HMAC-SHA256", methods["/v2/feed"])
+	assert.Equal(t, "HMAC-SHA1", methods["/v1/feed"])
+}
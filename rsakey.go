@@ -0,0 +1,126 @@
+package oauth1
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+)
+
+// ParseRSAPrivateKey parses an RSA private key from the first PEM block of
+// pemBytes, in PKCS#1 ("RSA PRIVATE KEY") or PKCS#8 ("PRIVATE KEY") form.
+// A block encrypted per RFC 1423 is decrypted with passphrase, which is
+// otherwise ignored and may be nil. RFC 1423 encryption is insecure by
+// design: it has no integrity check, so a wrong passphrase or a tampered
+// block is not always detected, and it is only supported to load existing
+// keys. Prefer unencrypted keys kept in a secret store.
+func ParseRSAPrivateKey(pemBytes, passphrase []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("oauth1: No PEM block found")
+	}
+	der := block.Bytes
+	//lint:ignore SA1019 legacy RFC 1423 encryption, documented as insecure
+	if x509.IsEncryptedPEMBlock(block) {
+		if len(passphrase) == 0 {
+			return nil, errors.New("oauth1: PEM block is encrypted and no passphrase was given")
+		}
+		var err error
+		//lint:ignore SA1019 legacy RFC 1423 encryption, documented as insecure
+		if der, err = x509.DecryptPEMBlock(block, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(der)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("oauth1: PKCS#8 private key is not an RSA key")
+		}
+		return rsaKey, nil
+	case "ENCRYPTED PRIVATE KEY":
+		return nil, errors.New("oauth1: Encrypted PKCS#8 keys are not supported, decrypt the key first")
+	}
+	return nil, errors.New("oauth1: Unsupported PEM block type " + block.Type)
+}
+
+// LoadRSAPrivateKey reads the named PEM file and parses it with
+// ParseRSAPrivateKey.
+func LoadRSAPrivateKey(filename string, passphrase []byte) (*rsa.PrivateKey, error) {
+	pemBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRSAPrivateKey(pemBytes, passphrase)
+}
+
+// NewRSASignerFromPEM returns an RSASigner (RSA-SHA1) with the private key
+// parsed from pemBytes by ParseRSAPrivateKey.
+func NewRSASignerFromPEM(pemBytes, passphrase []byte) (RSASigner, error) {
+	key, err := ParseRSAPrivateKey(pemBytes, passphrase)
+	if err != nil {
+		return RSASigner{}, err
+	}
+	return RSASigner{PrivateKey: key}, nil
+}
+
+// NewRSASignerFromFile returns an RSASigner (RSA-SHA1) with the private key
+// loaded from the named PEM file by LoadRSAPrivateKey.
+func NewRSASignerFromFile(filename string, passphrase []byte) (RSASigner, error) {
+	key, err := LoadRSAPrivateKey(filename, passphrase)
+	if err != nil {
+		return RSASigner{}, err
+	}
+	return RSASigner{PrivateKey: key}, nil
+}
+
+// ParseRSAPublicKey parses an RSA public key from the first PEM block of
+// pemBytes, in PKIX ("PUBLIC KEY") or PKCS#1 ("RSA PUBLIC KEY") form, or
+// from an X.509 certificate ("CERTIFICATE"), as consumers register them with
+// providers.
+func ParseRSAPublicKey(pemBytes []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("oauth1: No PEM block found")
+	}
+	var key interface{}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		var err error
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	default:
+		return nil, errors.New("oauth1: Unsupported PEM block type " + block.Type)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("oauth1: Public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// LoadRSAPublicKey reads the named PEM file and parses it with
+// ParseRSAPublicKey.
+func LoadRSAPublicKey(filename string) (*rsa.PublicKey, error) {
+	pemBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRSAPublicKey(pemBytes)
+}
@@ -0,0 +1,127 @@
+package oauth1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestParseRSAPrivateKey_PKCS1(t *testing.T) {
+	key := newTestRSAKey(t)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	parsed, err := ParseRSAPrivateKey(pemBytes, nil)
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(parsed))
+}
+
+func TestParseRSAPrivateKey_PKCS8(t *testing.T) {
+	key := newTestRSAKey(t)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	parsed, err := ParseRSAPrivateKey(pemBytes, nil)
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(parsed))
+}
+
+func TestParseRSAPrivateKey_Encrypted(t *testing.T) {
+	key := newTestRSAKey(t)
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte("passphrase"), x509.PEMCipherAES256)
+	assert.Nil(t, err)
+	pemBytes := pem.EncodeToMemory(block)
+
+	parsed, err := ParseRSAPrivateKey(pemBytes, []byte("passphrase"))
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = ParseRSAPrivateKey(pemBytes, nil)
+	assert.EqualError(t, err, "oauth1: PEM block is encrypted and no passphrase was given")
+	_, err = ParseRSAPrivateKey(pemBytes, []byte("wrong"))
+	assert.NotNil(t, err)
+}
+
+func TestParseRSAPrivateKey_Invalid(t *testing.T) {
+	_, err := ParseRSAPrivateKey([]byte("not a key"), nil)
+	assert.EqualError(t, err, "oauth1: No PEM block found")
+
+	_, err = ParseRSAPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0}}), nil)
+	assert.EqualError(t, err, "oauth1: Unsupported PEM block type CERTIFICATE")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	assert.Nil(t, err)
+	_, err = ParseRSAPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil)
+	assert.EqualError(t, err, "oauth1: PKCS#8 private key is not an RSA key")
+}
+
+func TestNewRSASignerFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oauth1")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	key := newTestRSAKey(t)
+	filename := filepath.Join(dir, "consumer.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	assert.Nil(t, ioutil.WriteFile(filename, pemBytes, 0600))
+
+	signer, err := NewRSASignerFromFile(filename, nil)
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(signer.PrivateKey))
+
+	signer, err = NewRSASignerFromPEM(pemBytes, nil)
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(signer.PrivateKey))
+
+	_, err = NewRSASignerFromFile(filepath.Join(dir, "missing.pem"), nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseRSAPublicKey(t *testing.T) {
+	key := newTestRSAKey(t)
+	pkixDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "consumer"}}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	for _, block := range []*pem.Block{
+		{Type: "PUBLIC KEY", Bytes: pkixDER},
+		{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)},
+		{Type: "CERTIFICATE", Bytes: cert},
+	} {
+		parsed, err := ParseRSAPublicKey(pem.EncodeToMemory(block))
+		assert.Nil(t, err, block.Type)
+		assert.True(t, key.PublicKey.Equal(parsed), block.Type)
+	}
+}
+
+func TestParseRSAPublicKey_Invalid(t *testing.T) {
+	_, err := ParseRSAPublicKey([]byte("not pem"))
+	assert.EqualError(t, err, "oauth1: No PEM block found")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	assert.Nil(t, err)
+	_, err = ParseRSAPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.EqualError(t, err, "oauth1: Public key is not an RSA key")
+}
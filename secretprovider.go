@@ -0,0 +1,20 @@
+package oauth1
+
+// A SecretProvider supplies the consumer secret each time a request is
+// signed, for example from Vault or AWS Secrets Manager, so that a rotated
+// secret takes effect without rebuilding clients. Token secrets are
+// resolved per request likewise by a TokenSource. Implementations must be
+// safe for concurrent use and should cache secrets, as ConsumerSecret is
+// called for every signature.
+type SecretProvider interface {
+	ConsumerSecret() (string, error)
+}
+
+// SecretProviderFunc is an adapter to allow the use of ordinary functions as
+// a SecretProvider.
+type SecretProviderFunc func() (string, error)
+
+// ConsumerSecret calls f().
+func (f SecretProviderFunc) ConsumerSecret() (string, error) {
+	return f()
+}
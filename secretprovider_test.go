@@ -0,0 +1,88 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rotatingSecret is a SecretProvider whose secret can be rotated.
+type rotatingSecret struct {
+	mu     sync.Mutex
+	secret string
+}
+
+func (s *rotatingSecret) ConsumerSecret() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.secret, nil
+}
+
+func (s *rotatingSecret) rotate(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secret = secret
+}
+
+func TestConfigClient_SecretProvider(t *testing.T) {
+	var signatures []string
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		signatures = append(signatures, params["oauth_signature"])
+	})
+	defer server.Close()
+
+	secrets := &rotatingSecret{secret: "old_secret"}
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "ignored",
+		SecretProvider: secrets,
+		Signer:         PlaintextSigner{},
+	}
+	client := config.Client(NoContext, "token", "token_secret")
+	_, err := client.Get(server.URL)
+	assert.Nil(t, err)
+	secrets.rotate("new_secret")
+	_, err = client.Get(server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		url.QueryEscape("old_secret&token_secret"),
+		url.QueryEscape("new_secret&token_secret"),
+	}, signatures)
+}
+
+func TestConfigClient_SecretProviderError(t *testing.T) {
+	config := &Config{
+		ConsumerKey: "consumer_key",
+		SecretProvider: SecretProviderFunc(func() (string, error) {
+			return "", errors.New("vault sealed")
+		}),
+	}
+	_, err := config.Client(NoContext, "token", "token_secret").Get("https://api.example.com/feed")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "vault sealed")
+	}
+}
+
+func TestPoolSign_SecretProvider(t *testing.T) {
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		SecretProvider: SecretProviderFunc(func() (string, error) { return "consumer_secret", nil }),
+	}
+	token := &Token{Token: "token", TokenSecret: "token_secret"}
+	req, err := http.NewRequest("GET", "https://api.example.com/feed", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, NewPool(config, token, 1).SignAll([]*http.Request{req}))
+
+	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+	expected, err := (&Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}).signing().authorizationHeader(
+		req, token.TokenSecret, map[string][]string{"oauth_token": {token.Token}},
+		stamp{params["oauth_nonce"], time.Unix(mustParseInt(t, params["oauth_timestamp"]), 0)})
+	assert.Nil(t, err)
+	assert.Equal(t, parseOAuthParamsOrFail(t, expected)["oauth_signature"], params["oauth_signature"])
+}
@@ -0,0 +1,99 @@
+package oauth1
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ktnyt/oauth1/internal"
+)
+
+// DefaultSecretTTL is how long a MemorySecretStore keeps request secrets
+// when no TTL is set.
+const DefaultSecretTTL = 15 * time.Minute
+
+// MemorySecretStore keeps request secrets (temporary credential secrets) in
+// memory between RequestToken and AccessToken, keyed by request token.
+// Entries expire after TTL; call StartJanitor to prune them in the background
+// rather than only on access. A MemorySecretStore is safe for concurrent use.
+type MemorySecretStore struct {
+	// TTL of stored secrets, DefaultSecretTTL if zero
+	TTL time.Duration
+
+	mu      sync.Mutex
+	secrets map[string]storedSecret
+	janitor internal.Janitor
+	now     func() time.Time
+}
+
+type storedSecret struct {
+	secret  string
+	expires time.Time
+}
+
+// Put stores the request secret of requestToken.
+func (s *MemorySecretStore) Put(requestToken, requestSecret string) {
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultSecretTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.secrets == nil {
+		s.secrets = make(map[string]storedSecret)
+	}
+	s.secrets[requestToken] = storedSecret{requestSecret, s.clock().Add(ttl)}
+}
+
+// Take removes and returns the request secret of requestToken. It reports
+// false if the secret was never stored, already taken or has expired.
+func (s *MemorySecretStore) Take(requestToken string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.secrets[requestToken]
+	if !ok {
+		return "", false
+	}
+	delete(s.secrets, requestToken)
+	if !s.clock().Before(stored.expires) {
+		return "", false
+	}
+	return stored.secret, true
+}
+
+// Len returns the number of stored secrets, including expired ones which
+// have not been pruned yet.
+func (s *MemorySecretStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.secrets)
+}
+
+// Prune removes expired secrets.
+func (s *MemorySecretStore) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock()
+	for token, stored := range s.secrets {
+		if !now.Before(stored.expires) {
+			delete(s.secrets, token)
+		}
+	}
+}
+
+// StartJanitor prunes expired secrets every interval, or every minute if
+// interval is not positive, in a background goroutine until Stop is called.
+func (s *MemorySecretStore) StartJanitor(interval time.Duration) {
+	s.janitor.Start(interval, s.Prune)
+}
+
+// Stop stops the janitor goroutine, if running.
+func (s *MemorySecretStore) Stop() {
+	s.janitor.Stop()
+}
+
+func (s *MemorySecretStore) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,53 @@
+package oauth1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemorySecretStore(t *testing.T) {
+	store := &MemorySecretStore{}
+	store.Put("request_token", "request_secret")
+	secret, ok := store.Take("request_token")
+	assert.True(t, ok)
+	assert.Equal(t, "request_secret", secret)
+
+	// secrets are single use
+	_, ok = store.Take("request_token")
+	assert.False(t, ok)
+	_, ok = store.Take("unknown_token")
+	assert.False(t, ok)
+}
+
+func TestMemorySecretStore_Expiry(t *testing.T) {
+	now := time.Unix(1318467427, 0)
+	store := &MemorySecretStore{TTL: time.Minute, now: func() time.Time { return now }}
+	store.Put("old_token", "old_secret")
+	now = now.Add(30 * time.Second)
+	store.Put("new_token", "new_secret")
+	now = now.Add(30 * time.Second)
+
+	store.Prune()
+	assert.Equal(t, 1, store.Len())
+	_, ok := store.Take("old_token")
+	assert.False(t, ok)
+	secret, ok := store.Take("new_token")
+	assert.True(t, ok)
+	assert.Equal(t, "new_secret", secret)
+}
+
+func TestMemorySecretStore_Janitor(t *testing.T) {
+	store := &MemorySecretStore{TTL: time.Nanosecond}
+	store.Put("request_token", "request_secret")
+	store.StartJanitor(time.Millisecond)
+	defer store.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for store.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 0, store.Len())
+	store.Stop()
+}
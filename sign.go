@@ -0,0 +1,48 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SignRequest signs req with the Config's consumer credentials and token and
+// sets its Authorization header in place, for requests sent by means other
+// than a Client. A form body is read and replaced with an equivalent
+// reader. If token is nil, req is signed with the consumer credentials only.
+func (c *Config) SignRequest(req *http.Request, token *Token) error {
+	oauthParams, tokenSecret := tokenParams(token)
+	st := stamp{newNonce(c.Noncer), signingTime(c.Clock)}
+	header, err := c.signing().authorizationHeader(req, tokenSecret, oauthParams, st)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", header)
+	return nil
+}
+
+// AuthorizationHeader returns the value of a signed OAuth Authorization
+// header for req, without setting it, for embedding signatures in queue
+// messages or other payloads. Aside from a form body being replaced with an
+// equivalent reader, req is left unchanged.
+func AuthorizationHeader(consumerKey, consumerSecret, token, tokenSecret string, req *http.Request) (string, error) {
+	config := &Config{ConsumerKey: consumerKey, ConsumerSecret: consumerSecret}
+	oauthParams, _ := tokenParams(&Token{Token: token})
+	st := stamp{newNonce(nil), time.Now()}
+	return config.signing().authorizationHeader(req, tokenSecret, oauthParams, st)
+}
+
+// tokenParams returns the oauth_token protocol parameter and the token
+// secret to sign with token. Like a Transport, two-legged requests, whose
+// token is nil or empty, carry no oauth_token, so every signing API yields
+// the same base string for them.
+func tokenParams(token *Token) (url.Values, string) {
+	oauthParams := make(url.Values)
+	if token == nil {
+		return oauthParams, ""
+	}
+	if token.Token != "" {
+		oauthParams.Add("oauth_token", token.Token)
+	}
+	return oauthParams, token.TokenSecret
+}
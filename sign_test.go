@@ -0,0 +1,117 @@
+package oauth1
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSignRequest(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	body := url.Values{"status": {"hello"}}.Encode()
+	req, err := http.NewRequest("POST", "https://api.example.com/1.1/statuses/update.json", strings.NewReader(body))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer stale")
+
+	err = config.SignRequest(req, &Token{Token: "token", TokenSecret: "token_secret"})
+	assert.Nil(t, err)
+	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+	assert.Equal(t, "consumer_key", params["oauth_consumer_key"])
+	assert.Equal(t, "token", params["oauth_token"])
+	assert.NotContains(t, params, "status")
+	assert.NotEmpty(t, params["oauth_signature"])
+
+	b, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, body, string(b))
+}
+
+func TestConfigSignRequest_NilToken(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	req, err := http.NewRequest("GET", "https://api.example.com/1.1/help/configuration.json", nil)
+	assert.Nil(t, err)
+
+	err = config.SignRequest(req, nil)
+	assert.Nil(t, err)
+	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+	assert.Equal(t, "consumer_key", params["oauth_consumer_key"])
+	_, ok := params["oauth_token"]
+	assert.False(t, ok)
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://api.example.com/1.1/statuses/home_timeline.json?count=5", nil)
+	assert.Nil(t, err)
+
+	header, err := AuthorizationHeader("consumer_key", "consumer_secret", "token", "token_secret", req)
+	assert.Nil(t, err)
+	assert.Empty(t, req.Header.Get("Authorization"))
+	params := parseOAuthParamsOrFail(t, header)
+	assert.Equal(t, "consumer_key", params["oauth_consumer_key"])
+	assert.Equal(t, "token", params["oauth_token"])
+	assert.Equal(t, "HMAC-SHA1", params["oauth_signature_method"])
+	assert.NotEmpty(t, params["oauth_signature"])
+}
+
+func TestTwoLeggedSigning(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	v := &Verifier{ConsumerSecret: lookupSecret(map[string]string{"consumer_key": "consumer_secret"})}
+	verify := func(name string, req *http.Request) {
+		params, err := v.Verify(req)
+		assert.Nil(t, err, name)
+		_, hasToken := params["oauth_token"]
+		assert.False(t, hasToken, name)
+	}
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest("GET", "https://api.example.com/feed?count=5", nil)
+		assert.Nil(t, err)
+		return req
+	}
+
+	for _, token := range []*Token{nil, {}} {
+		req := newRequest()
+		assert.Nil(t, config.SignRequest(req, token))
+		verify("SignRequest", req)
+
+		signed, err := config.SignAhead(newRequest(), token, time.Now(), time.Minute)
+		assert.Nil(t, err)
+		req, err = signed.Request()
+		assert.Nil(t, err)
+		verify("SignAhead", req)
+
+		u, err := config.SignURL("GET", "https://api.example.com/feed?count=5", token, time.Now())
+		assert.Nil(t, err)
+		verify("SignURL", httptest.NewRequest("GET", u.String(), nil))
+	}
+
+	req := newRequest()
+	header, err := AuthorizationHeader("consumer_key", "consumer_secret", "", "", req)
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", header)
+	verify("AuthorizationHeader", req)
+
+	req = newRequest()
+	assert.Nil(t, NewPool(config, &Token{}, 1).SignAll([]*http.Request{req}))
+	verify("Pool", req)
+
+	var transported *http.Request
+	client := &http.Client{Transport: &Transport{
+		consumerKey:    "consumer_key",
+		consumerSecret: "consumer_secret",
+		source:         StaticTokenSource(&Token{}),
+		Base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			transported = req
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}}
+	_, err = client.Get("https://api.example.com/feed?count=5")
+	assert.Nil(t, err)
+	verify("Transport", transported)
+}
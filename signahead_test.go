@@ -0,0 +1,86 @@
+package oauth1
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSignAhead(t *testing.T) {
+	at := time.Unix(1318467427, 0)
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	req, err := http.NewRequest("POST", "https://api.example.com/1/statuses/update.json", strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	signed, err := config.SignAhead(req, &Token{Token: "token", TokenSecret: "secret"}, at, 5*time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, at, signed.NotBefore)
+	assert.Equal(t, at.Add(5*time.Minute), signed.Expires)
+	assert.False(t, signed.Valid(at.Add(-time.Second)))
+	assert.True(t, signed.Valid(at))
+	assert.False(t, signed.Valid(at.Add(5*time.Minute)))
+
+	params := parseOAuthParamsOrFail(t, signed.Header.Get("Authorization"))
+	assert.Equal(t, "1318467427", params["oauth_timestamp"])
+	assert.Equal(t, "token", params["oauth_token"])
+	assert.NotContains(t, params, "status")
+
+	// the signed request survives serialization for another process
+	b, err := json.Marshal(signed)
+	assert.Nil(t, err)
+	var received SignedRequest
+	assert.Nil(t, json.Unmarshal(b, &received))
+	req2, err := received.Request()
+	assert.Nil(t, err)
+	assert.Equal(t, "POST", req2.Method)
+	assert.Equal(t, "https://api.example.com/1/statuses/update.json", req2.URL.String())
+	assert.Equal(t, signed.Header.Get("Authorization"), req2.Header.Get("Authorization"))
+	assert.Equal(t, "application/x-www-form-urlencoded", req2.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(req2.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "status=hello", string(body))
+}
+
+func TestConfigSignAhead_NoBody(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	req, err := http.NewRequest("GET", "https://api.example.com/1/statuses/home_timeline.json", nil)
+	assert.Nil(t, err)
+	signed, err := config.SignAhead(req, &Token{Token: "token", TokenSecret: "secret"}, time.Now(), time.Minute)
+	assert.Nil(t, err)
+	assert.Nil(t, signed.Body)
+	req2, err := signed.Request()
+	assert.Nil(t, err)
+	assert.Nil(t, req2.Body)
+}
+
+func TestConfigSignAhead_ParamsInQuery(t *testing.T) {
+	at := time.Unix(1318467427, 0)
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		Endpoint:       Endpoint{ParamsInQuery: true},
+	}
+	req, err := http.NewRequest("GET", "https://api.example.com/1/statuses/home_timeline.json?count=5", nil)
+	assert.Nil(t, err)
+
+	signed, err := config.SignAhead(req, nil, at, time.Minute)
+	assert.Nil(t, err)
+	assert.Empty(t, signed.Header.Get("Authorization"))
+	u, err := url.Parse(signed.URL)
+	assert.Nil(t, err)
+	query := u.Query()
+	assert.Equal(t, "5", query.Get("count"))
+	assert.Equal(t, "consumer_key", query.Get("oauth_consumer_key"))
+	assert.Equal(t, "1318467427", query.Get("oauth_timestamp"))
+	assert.NotEmpty(t, query.Get("oauth_signature"))
+	assert.NotContains(t, query, "oauth_token")
+	// req itself is left unsigned
+	assert.Equal(t, "count=5", req.URL.RawQuery)
+}
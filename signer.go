@@ -0,0 +1,169 @@
+package oauth1
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// A Signer computes signatures with an OAuth1 signature method. Sign is
+// given the signing key, the consumer secret and token secret joined by
+// "&", and the signature base string, and returns the value of
+// oauth_signature. Implementations must be safe for concurrent use.
+type Signer interface {
+	// Name returns the oauth_signature_method, such as "HMAC-SHA1"
+	Name() string
+
+	// Sign returns the signature of base made with key
+	Sign(key, base string) (string, error)
+}
+
+// HMACSigner signs with the HMAC-SHA1 signature method, the default.
+type HMACSigner struct{}
+
+// Name returns "HMAC-SHA1".
+func (HMACSigner) Name() string {
+	return "HMAC-SHA1"
+}
+
+// Sign returns the base64 encoded HMAC-SHA1 digest of base.
+func (HMACSigner) Sign(key, base string) (string, error) {
+	return hmacSign(sha1.New, key, base)
+}
+
+// HMACSHA256Signer signs with the HMAC-SHA256 signature method, which some
+// providers require in place of HMAC-SHA1.
+type HMACSHA256Signer struct{}
+
+// Name returns "HMAC-SHA256".
+func (HMACSHA256Signer) Name() string {
+	return "HMAC-SHA256"
+}
+
+// Sign returns the base64 encoded HMAC-SHA256 digest of base.
+func (HMACSHA256Signer) Sign(key, base string) (string, error) {
+	return hmacSign(sha256.New, key, base)
+}
+
+func hmacSign(h func() hash.Hash, key, base string) (string, error) {
+	mac := hmac.New(h, []byte(key))
+	if _, err := mac.Write([]byte(base)); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// PlaintextSigner signs with the PLAINTEXT signature method, sending the
+// signing key itself as the signature. It must only be used over TLS.
+type PlaintextSigner struct{}
+
+// Name returns "PLAINTEXT".
+func (PlaintextSigner) Name() string {
+	return "PLAINTEXT"
+}
+
+// Sign returns key.
+func (PlaintextSigner) Sign(key, base string) (string, error) {
+	return key, nil
+}
+
+// RSASigner signs with the RSA-SHA1 signature method using PrivateKey. The
+// consumer and token secrets are not used.
+type RSASigner struct {
+	// PrivateKey is an *rsa.PrivateKey or any crypto.Signer with an RSA
+	// public key, such as a key held by a PKCS#11 device or a cloud KMS
+	PrivateKey crypto.Signer
+}
+
+// Name returns "RSA-SHA1".
+func (s RSASigner) Name() string {
+	return "RSA-SHA1"
+}
+
+// Sign returns the base64 encoded RSASSA-PKCS1-v1_5 signature of the SHA-1
+// digest of base.
+func (s RSASigner) Sign(key, base string) (string, error) {
+	return rsaSign(s.PrivateKey, crypto.SHA1, base)
+}
+
+// RSASHA256Signer signs with the RSA-SHA256 signature method using
+// PrivateKey. The consumer and token secrets are not used.
+type RSASHA256Signer struct {
+	// PrivateKey is an *rsa.PrivateKey or any crypto.Signer with an RSA
+	// public key, such as a key held by a PKCS#11 device or a cloud KMS
+	PrivateKey crypto.Signer
+}
+
+// Name returns "RSA-SHA256".
+func (s RSASHA256Signer) Name() string {
+	return "RSA-SHA256"
+}
+
+// Sign returns the base64 encoded RSASSA-PKCS1-v1_5 signature of the
+// SHA-256 digest of base.
+func (s RSASHA256Signer) Sign(key, base string) (string, error) {
+	return rsaSign(s.PrivateKey, crypto.SHA256, base)
+}
+
+func rsaSign(key crypto.Signer, hash crypto.Hash, base string) (string, error) {
+	if key == nil {
+		return "", errors.New("oauth1: RSA signer has no private key")
+	}
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return "", errors.New("oauth1: RSA signer's private key is not an RSA key")
+	}
+	h := hash.New()
+	h.Write([]byte(base))
+	// with a crypto.Hash as options, RSA keys sign with RSASSA-PKCS1-v1_5
+	signature, err := key.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// signer returns the Config's Signer or else the one of the signature method
+// its Endpoint requires, nil for the default HMACSigner.
+func (c *Config) signer() Signer {
+	if c.Signer != nil || c.Endpoint.SignatureMethod == "" {
+		return c.Signer
+	}
+	return methodSigner(c.Endpoint.SignatureMethod)
+}
+
+// methodSigner returns a Signer for the named signature method. RSA signers
+// lack a private key and fail to sign; unknown methods always fail to sign.
+func methodSigner(name string) Signer {
+	switch name {
+	case "HMAC-SHA1":
+		return HMACSigner{}
+	case "HMAC-SHA256":
+		return HMACSHA256Signer{}
+	case "PLAINTEXT":
+		return PlaintextSigner{}
+	case "RSA-SHA1":
+		return RSASigner{}
+	case "RSA-SHA256":
+		return RSASHA256Signer{}
+	}
+	return unsupportedSigner(name)
+}
+
+// unsupportedSigner is the Signer of a signature method this package does
+// not implement.
+type unsupportedSigner string
+
+func (s unsupportedSigner) Name() string {
+	return string(s)
+}
+
+func (s unsupportedSigner) Sign(key, base string) (string, error) {
+	return "", fmt.Errorf("oauth1: Unsupported signature method %s", string(s))
+}
@@ -0,0 +1,176 @@
+package oauth1
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACSigner(t *testing.T) {
+	mac := hmac.New(sha1.New, []byte("consumer_secret&token_secret"))
+	mac.Write([]byte("base"))
+	signature, err := HMACSigner{}.Sign("consumer_secret&token_secret", "base")
+	assert.Nil(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), signature)
+	assert.Equal(t, "HMAC-SHA1", HMACSigner{}.Name())
+}
+
+func TestHMACSHA256Signer(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("consumer_secret&token_secret"))
+	mac.Write([]byte("base"))
+	signature, err := HMACSHA256Signer{}.Sign("consumer_secret&token_secret", "base")
+	assert.Nil(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), signature)
+	assert.Equal(t, "HMAC-SHA256", HMACSHA256Signer{}.Name())
+}
+
+func TestPlaintextSigner(t *testing.T) {
+	signature, err := PlaintextSigner{}.Sign("consumer_secret&token_secret", "base")
+	assert.Nil(t, err)
+	assert.Equal(t, "consumer_secret&token_secret", signature)
+	assert.Equal(t, "PLAINTEXT", PlaintextSigner{}.Name())
+}
+
+func TestRSASigner(t *testing.T) {
+	key := newTestRSAKey(t)
+	cases := []struct {
+		signer Signer
+		name   string
+		hash   crypto.Hash
+	}{
+		{RSASigner{PrivateKey: key}, "RSA-SHA1", crypto.SHA1},
+		{RSASHA256Signer{PrivateKey: key}, "RSA-SHA256", crypto.SHA256},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.name, c.signer.Name())
+		signature, err := c.signer.Sign("ignored&", "base")
+		assert.Nil(t, err)
+		decoded, err := base64.StdEncoding.DecodeString(signature)
+		assert.Nil(t, err)
+		h := c.hash.New()
+		h.Write([]byte("base"))
+		assert.Nil(t, rsa.VerifyPKCS1v15(&key.PublicKey, c.hash, h.Sum(nil), decoded))
+	}
+}
+
+func TestRSASigner_NoPrivateKey(t *testing.T) {
+	_, err := RSASigner{}.Sign("", "base")
+	assert.EqualError(t, err, "oauth1: RSA signer has no private key")
+}
+
+// hsmKey is a crypto.Signer standing in for a key held by a PKCS#11 device
+// or a cloud KMS.
+type hsmKey struct {
+	key   *rsa.PrivateKey
+	signs int
+}
+
+func (k *hsmKey) Public() crypto.PublicKey {
+	return k.key.Public()
+}
+
+func (k *hsmKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	k.signs++
+	return k.key.Sign(rand, digest, opts)
+}
+
+func TestRSASigner_CryptoSigner(t *testing.T) {
+	key := &hsmKey{key: newTestRSAKey(t)}
+	signature, err := RSASigner{PrivateKey: key}.Sign("", "base")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, key.signs)
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	assert.Nil(t, err)
+	digest := sha1.Sum([]byte("base"))
+	assert.Nil(t, rsa.VerifyPKCS1v15(&key.key.PublicKey, crypto.SHA1, digest[:], decoded))
+}
+
+func TestRSASigner_NotRSAKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	_, err = RSASHA256Signer{PrivateKey: ecKey}.Sign("", "base")
+	assert.EqualError(t, err, "oauth1: RSA signer's private key is not an RSA key")
+}
+
+func TestConfigClient_Signer(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "PLAINTEXT", params["oauth_signature_method"])
+		assert.Equal(t, url.QueryEscape("consumer_secret&token_secret"), params["oauth_signature"])
+	})
+	defer server.Close()
+
+	config := NewConfig("consumer_key", "consumer_secret", WithSigner(PlaintextSigner{}))
+	_, err := config.Client(NoContext, "token", "token_secret").Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestPoolSign_Signer(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret", Signer: PlaintextSigner{}}
+	req, err := http.NewRequest("GET", "https://api.example.com/feed", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, NewPool(config, &Token{Token: "token", TokenSecret: "token_secret"}, 1).SignAll([]*http.Request{req}))
+	params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+	assert.Equal(t, "PLAINTEXT", params["oauth_signature_method"])
+	assert.Equal(t, url.QueryEscape("consumer_secret&token_secret"), params["oauth_signature"])
+}
+
+func TestConfigClient_EndpointSignatureMethod(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "PLAINTEXT", params["oauth_signature_method"])
+		assert.Equal(t, url.QueryEscape("consumer_secret&token_secret"), params["oauth_signature"])
+	})
+	defer server.Close()
+
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		Endpoint:       Endpoint{SignatureMethod: "PLAINTEXT"},
+	}
+	_, err := config.Client(NoContext, "token", "token_secret").Get(server.URL)
+	assert.Nil(t, err)
+}
+
+func TestConfigSigner(t *testing.T) {
+	key := newTestRSAKey(t)
+	cases := []struct {
+		config   *Config
+		expected Signer
+	}{
+		{&Config{}, nil},
+		{&Config{Endpoint: Endpoint{SignatureMethod: "HMAC-SHA1"}}, HMACSigner{}},
+		{&Config{Endpoint: Endpoint{SignatureMethod: "HMAC-SHA256"}}, HMACSHA256Signer{}},
+		{&Config{Endpoint: Endpoint{SignatureMethod: "PLAINTEXT"}}, PlaintextSigner{}},
+		{&Config{Endpoint: Endpoint{SignatureMethod: "RSA-SHA1"}}, RSASigner{}},
+		{&Config{Endpoint: Endpoint{SignatureMethod: "RSA-SHA256"}}, RSASHA256Signer{}},
+		// a Signer of the Config takes precedence
+		{&Config{Signer: RSASigner{PrivateKey: key}, Endpoint: Endpoint{SignatureMethod: "RSA-SHA1"}}, RSASigner{PrivateKey: key}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.config.signer())
+	}
+}
+
+func TestConfigSignRequest_EndpointSignatureMethodErrors(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://api.example.com/feed", nil)
+	assert.Nil(t, err)
+
+	config := &Config{Endpoint: Endpoint{SignatureMethod: "RSA-SHA1"}}
+	assert.EqualError(t, config.SignRequest(req, nil), "oauth1: RSA signer has no private key")
+
+	config = &Config{Endpoint: Endpoint{SignatureMethod: "HMAC-MD5"}}
+	assert.EqualError(t, config.SignRequest(req, nil), "oauth1: Unsupported signature method HMAC-MD5")
+}
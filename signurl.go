@@ -0,0 +1,30 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SignURL returns rawurl with the signed OAuth protocol parameters
+// (oauth_signature included) appended to its query, per RFC 5849 3.5.3, for
+// handing links to protected resources to browsers, image tags or third
+// parties which cannot set headers. The signature is valid for requests
+// with the given method only.
+//
+// OAuth1 links carry no explicit expiry: providers accept a signature for a
+// limited window around its oauth_timestamp, which is at. Pass time.Now()
+// for links used right away or a time in the near future to delay expiry.
+func (c *Config) SignURL(method, rawurl string, token *Token, at time.Time) (*url.URL, error) {
+	req, err := http.NewRequest(method, rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+	oauthParams, tokenSecret := tokenParams(token)
+	s := c.signing()
+	s.inQuery = true
+	if err := s.authorize(req, tokenSecret, oauthParams, stamp{newNonce(c.Noncer), at}); err != nil {
+		return nil, err
+	}
+	return req.URL, nil
+}
@@ -0,0 +1,58 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSignURL(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	at := time.Unix(1318467427, 0)
+	token := &Token{Token: "token", TokenSecret: "token_secret"}
+	signed, err := config.SignURL("GET", "https://api.example.com/photos/1.jpg?size=large", token, at)
+	assert.Nil(t, err)
+
+	query := signed.Query()
+	assert.Equal(t, "large", query.Get("size"))
+	assert.Equal(t, "consumer_key", query.Get("oauth_consumer_key"))
+	assert.Equal(t, "token", query.Get("oauth_token"))
+	assert.Equal(t, "1318467427", query.Get("oauth_timestamp"))
+	assert.Equal(t, "HMAC-SHA1", query.Get("oauth_signature_method"))
+	assert.NotEmpty(t, query.Get("oauth_nonce"))
+
+	// the embedded signature is the one of the header-signed request
+	req, err := http.NewRequest("GET", "https://api.example.com/photos/1.jpg?size=large", nil)
+	assert.Nil(t, err)
+	header, err := config.signing().authorizationHeader(req, token.TokenSecret,
+		map[string][]string{"oauth_token": {"token"}}, stamp{query.Get("oauth_nonce"), at})
+	assert.Nil(t, err)
+	expected, err := url.QueryUnescape(parseOAuthParamsOrFail(t, header)["oauth_signature"])
+	assert.Nil(t, err)
+	assert.Equal(t, expected, signed.Query().Get("oauth_signature"))
+}
+
+func TestConfigSignURL_ExtraParams(t *testing.T) {
+	config := &Config{
+		ConsumerKey:    "consumer_key",
+		ConsumerSecret: "consumer_secret",
+		ExtraParams:    url.Values{"x_auth_mode": {"client_auth"}},
+	}
+	signed, err := config.SignURL("GET", "https://api.example.com/feed", nil, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, "client_auth", signed.Query().Get("x_auth_mode"))
+
+	v := &Verifier{ConsumerSecret: lookupSecret(map[string]string{"consumer_key": "consumer_secret"})}
+	_, err = v.Verify(httptest.NewRequest("GET", signed.String(), nil))
+	assert.Nil(t, err)
+}
+
+func TestConfigSignURL_InvalidURL(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key"}
+	_, err := config.SignURL("GET", "://missing-scheme", nil, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package oauth1
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of process-wide counters of the OAuth layer.
+type Stats struct {
+	// RequestsSigned counts signed requests
+	RequestsSigned int64 `json:"requests_signed"`
+
+	// SignatureFailures counts requests which could not be signed
+	SignatureFailures int64 `json:"signature_failures"`
+
+	// TokenRefreshes counts token refreshes
+	TokenRefreshes int64 `json:"token_refreshes"`
+
+	// NonceGenerations counts generated nonces
+	NonceGenerations int64 `json:"nonce_generations"`
+
+	// ClockSkew is how far provider clocks were ahead of the local clock
+	// (negative if behind), according to the Date header of the latest
+	// token endpoint response
+	ClockSkew time.Duration `json:"clock_skew_ns"`
+}
+
+var stats struct {
+	requestsSigned    int64
+	signatureFailures int64
+	tokenRefreshes    int64
+	nonceGenerations  int64
+	clockSkew         int64
+}
+
+// ReadStats returns a snapshot of the counters.
+func ReadStats() Stats {
+	return Stats{
+		RequestsSigned:    atomic.LoadInt64(&stats.requestsSigned),
+		SignatureFailures: atomic.LoadInt64(&stats.signatureFailures),
+		TokenRefreshes:    atomic.LoadInt64(&stats.tokenRefreshes),
+		NonceGenerations:  atomic.LoadInt64(&stats.nonceGenerations),
+		ClockSkew:         time.Duration(atomic.LoadInt64(&stats.clockSkew)),
+	}
+}
+
+// StatsVar returns an expvar.Var reporting the counters, to be published
+// with expvar.Publish("oauth1", oauth1.StatsVar()).
+func StatsVar() expvar.Var {
+	return expvar.Func(func() interface{} { return ReadStats() })
+}
+
+// DebugHandler returns an http.Handler serving the counters as JSON, for
+// operators inspecting a running service. Mount it on an internal address.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ReadStats())
+	})
+}
+
+// recordSignature counts a signing attempt which failed if err is non-nil.
+func recordSignature(err error) {
+	if err != nil {
+		atomic.AddInt64(&stats.signatureFailures, 1)
+		return
+	}
+	atomic.AddInt64(&stats.requestsSigned, 1)
+}
+
+// recordClockSkew records the skew of the provider clock reported in the
+// Date header of res, if any.
+func recordClockSkew(res *http.Response) {
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	atomic.StoreInt64(&stats.clockSkew, int64(date.Sub(time.Now()).Truncate(time.Second)))
+}
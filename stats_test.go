@@ -0,0 +1,58 @@
+package oauth1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	before := ReadStats()
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {})
+	defer server.Close()
+	_, err := NewClient(NoContext, "consumer_key", "consumer_secret", "token", "secret").Get(server.URL)
+	assert.Nil(t, err)
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("%gh&%ij"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = NewClient(NoContext, "consumer_key", "consumer_secret", "token", "secret").Do(req)
+	assert.Error(t, err)
+
+	after := ReadStats()
+	assert.True(t, after.RequestsSigned >= before.RequestsSigned+1)
+	assert.True(t, after.SignatureFailures >= before.SignatureFailures+1)
+	assert.True(t, after.NonceGenerations >= before.NonceGenerations+1)
+}
+
+func TestStats_ClockSkew(t *testing.T) {
+	data := url.Values{"oauth_token": {"token"}, "oauth_token_secret": {"secret"}}
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		w.Write([]byte(data.Encode()))
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{AccessTokenURL: server.URL}}
+	_, _, err := config.AccessToken("request_token", "request_secret", "verifier")
+	assert.Nil(t, err)
+	skew := ReadStats().ClockSkew
+	assert.True(t, skew > 59*time.Minute && skew <= time.Hour, "unexpected skew %s", skew)
+}
+
+func TestDebugHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/oauth1", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var snapshot Stats
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+
+	var published Stats
+	assert.Nil(t, json.Unmarshal([]byte(StatsVar().String()), &published))
+}
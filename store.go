@@ -0,0 +1,103 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNoToken is returned by a TokenStore when no Token is stored for a key.
+var ErrNoToken = errors.New("oauth1: no token stored")
+
+// TokenStore persists Tokens by key, typically one key per user.
+// Implementations must be safe for concurrent use.
+type TokenStore interface {
+	// Load returns the Token stored for key or ErrNoToken.
+	Load(key string) (*Token, error)
+
+	// Save stores token for key, replacing any previous Token.
+	Save(key string, token *Token) error
+}
+
+// PersistentClient returns an HTTP client signing requests with Tokens from
+// src. Whenever src yields a Token which differs from the last one seen, for
+// example after a refresh, it is saved to store under key before the request
+// is sent. If src is nil, the Token stored under key is used and never
+// saved back. The returned client and its Transport should not be modified.
+func (c *Config) PersistentClient(ctx context.Context, key string, src TokenSource, store TokenStore) *http.Client {
+	if src == nil {
+		return c.TokenSourceClient(ctx, storedTokenSource{key, store})
+	}
+	return c.TokenSourceClient(ctx, &persistingTokenSource{key: key, src: src, store: store})
+}
+
+// storedTokenSource is a TokenSource returning the Token stored for a key.
+type storedTokenSource struct {
+	key   string
+	store TokenStore
+}
+
+func (s storedTokenSource) Token() (*Token, error) {
+	return s.store.Load(s.key)
+}
+
+// persistingTokenSource saves new Tokens from src to store.
+type persistingTokenSource struct {
+	key   string
+	src   TokenSource
+	store TokenStore
+
+	mu   sync.Mutex
+	last Token
+}
+
+func (s *persistingTokenSource) Token() (*Token, error) {
+	return s.TokenContext(context.Background())
+}
+
+// TokenContext is like Token but passes ctx to src if it is a
+// ContextTokenSource.
+func (s *persistingTokenSource) TokenContext(ctx context.Context) (*Token, error) {
+	token, err := sourceToken(ctx, s.src)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if *token != s.last {
+		if err := s.store.Save(s.key, token); err != nil {
+			return nil, err
+		}
+		s.last = *token
+	}
+	return token, nil
+}
+
+// MemoryTokenStore is a TokenStore keeping Tokens in memory.
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+// Load returns the Token stored for key or ErrNoToken.
+func (s *MemoryTokenStore) Load(key string) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, ErrNoToken
+	}
+	return &token, nil
+}
+
+// Save stores a copy of token for key.
+func (s *MemoryTokenStore) Save(key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = make(map[string]Token)
+	}
+	s.tokens[key] = *token
+	return nil
+}
@@ -0,0 +1,97 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rotatingTokenSource returns the next of its tokens on each call.
+type rotatingTokenSource struct {
+	mu     sync.Mutex
+	tokens []*Token
+	calls  int
+}
+
+func (s *rotatingTokenSource) Token() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := s.tokens[s.calls%len(s.tokens)]
+	s.calls++
+	return token, nil
+}
+
+// countingTokenStore counts the Tokens saved to a MemoryTokenStore.
+type countingTokenStore struct {
+	MemoryTokenStore
+	saves int
+}
+
+func (s *countingTokenStore) Save(key string, token *Token) error {
+	s.saves++
+	return s.MemoryTokenStore.Save(key, token)
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	store := &MemoryTokenStore{}
+	_, err := store.Load("user")
+	assert.Equal(t, ErrNoToken, err)
+
+	token := &Token{Token: "token", TokenSecret: "secret"}
+	assert.Nil(t, store.Save("user", token))
+	token.Token = "modified"
+	loaded, err := store.Load("user")
+	assert.Nil(t, err)
+	assert.Equal(t, &Token{Token: "token", TokenSecret: "secret"}, loaded)
+}
+
+func TestConfigPersistentClient(t *testing.T) {
+	var seen []string
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		seen = append(seen, params["oauth_token"])
+	})
+	defer server.Close()
+
+	src := &rotatingTokenSource{tokens: []*Token{
+		{Token: "first", TokenSecret: "first_secret"},
+		{Token: "first", TokenSecret: "first_secret"},
+		{Token: "refreshed", TokenSecret: "refreshed_secret"},
+	}}
+	store := &countingTokenStore{}
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	client := config.PersistentClient(NoContext, "user", src, store)
+	for i := 0; i < 3; i++ {
+		_, err := client.Get(server.URL)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []string{"first", "first", "refreshed"}, seen)
+	assert.Equal(t, 2, store.saves)
+	token, err := store.Load("user")
+	assert.Nil(t, err)
+	assert.Equal(t, "refreshed", token.Token)
+}
+
+func TestConfigPersistentClient_FromStore(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "stored", params["oauth_token"])
+	})
+	defer server.Close()
+
+	store := &countingTokenStore{}
+	store.MemoryTokenStore.Save("user", &Token{Token: "stored", TokenSecret: "stored_secret"})
+	config := &Config{ConsumerKey: "consumer_key"}
+	_, err := config.PersistentClient(NoContext, "user", nil, store).Get(server.URL)
+	assert.Nil(t, err)
+	// the Token loaded from the store is not written back
+	assert.Equal(t, 0, store.saves)
+
+	_, err = config.PersistentClient(NoContext, "unknown", nil, store).Get(server.URL)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, ErrNoToken))
+	}
+}
@@ -0,0 +1,117 @@
+package oauth1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Token is an OAuth1 token (credential) and its shared secret.
+type Token struct {
+	Token       string
+	TokenSecret string
+
+	// Expiry of the token, zero if it does not expire
+	Expiry time.Time
+
+	// SessionHandle for refreshing the token with RefreshToken, if the
+	// provider supports the ScalableOAuth session extension
+	SessionHandle string
+}
+
+// tokenFromValues returns the Token described by the oauth_token,
+// oauth_token_secret and optional oauth_expires_in and oauth_session_handle
+// parameters of a token endpoint response. The oauth_token_secret may be
+// empty only if the Endpoint has an EmptyTokenSecret.
+func (c *Config) tokenFromValues(values url.Values) (*Token, error) {
+	token := &Token{
+		Token:         values.Get("oauth_token"),
+		TokenSecret:   values.Get("oauth_token_secret"),
+		SessionHandle: values.Get("oauth_session_handle"),
+	}
+	if token.Token == "" || token.TokenSecret == "" && !c.Endpoint.EmptyTokenSecret {
+		return nil, ErrMissingToken
+	}
+	if expiresIn := values.Get("oauth_expires_in"); expiresIn != "" {
+		seconds, err := strconv.ParseInt(expiresIn, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("oauth1: Invalid oauth_expires_in %q", expiresIn)
+		}
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
+
+// A TokenSource supplies the Token used to sign each request.
+// Implementations must be safe for concurrent use.
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// A ContextTokenSource is a TokenSource which may make requests to obtain a
+// Token, such as refreshes. A Transport calls TokenContext with the context
+// of the request it signs, so that cancelling the request cancels them too.
+type ContextTokenSource interface {
+	TokenSource
+	TokenContext(ctx context.Context) (*Token, error)
+}
+
+// sourceToken returns the Token of src, made with ctx if src is a
+// ContextTokenSource.
+func sourceToken(ctx context.Context, src TokenSource) (*Token, error) {
+	if cs, ok := src.(ContextTokenSource); ok {
+		return cs.TokenContext(ctx)
+	}
+	return src.Token()
+}
+
+// StaticTokenSource returns a TokenSource which always returns the same
+// Token. This is appropriate for tokens which do not have a time expiry.
+func StaticTokenSource(token *Token) TokenSource {
+	return staticTokenSource{token}
+}
+
+// staticTokenSource is a TokenSource that always returns the same Token.
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token() (*Token, error) {
+	if s.token == nil {
+		return nil, errors.New("oauth1: Token is nil")
+	}
+	return s.token, nil
+}
+
+// RequestTokenPair is like RequestToken but returns the request token and
+// secret as a *Token.
+func (c *Config) RequestTokenPair() (*Token, error) {
+	return c.RequestTokenPairContext(c.context())
+}
+
+// RequestTokenPairContext is like RequestTokenPair but makes the request
+// with ctx.
+func (c *Config) RequestTokenPairContext(ctx context.Context) (*Token, error) {
+	requestToken, requestSecret, err := c.RequestTokenContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{Token: requestToken, TokenSecret: requestSecret}, nil
+}
+
+// AccessTokenPair is like AccessToken but takes the request token as a
+// *Token and returns the access token and secret as a *Token, along with
+// its Expiry and SessionHandle if the provider sent them.
+func (c *Config) AccessTokenPair(requestToken *Token, verifier string) (*Token, error) {
+	return c.AccessTokenPairContext(c.context(), requestToken, verifier)
+}
+
+// AccessTokenPairContext is like AccessTokenPair but makes the request with
+// ctx.
+func (c *Config) AccessTokenPairContext(ctx context.Context, requestToken *Token, verifier string) (*Token, error) {
+	token, _, err := c.accessToken(ctx, requestToken.Token, requestToken.TokenSecret, verifier)
+	return token, err
+}
@@ -0,0 +1,111 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigRequestTokenPair(t *testing.T) {
+	data := url.Values{}
+	data.Add("oauth_token", "request_token")
+	data.Add("oauth_token_secret", "request_secret")
+	data.Add("oauth_callback_confirmed", "true")
+	server := newRequestTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: server.URL,
+		},
+	}
+	token, err := config.RequestTokenPair()
+	assert.Nil(t, err)
+	assert.Equal(t, &Token{Token: "request_token", TokenSecret: "request_secret"}, token)
+}
+
+func TestConfigRequestTokenPair_Error(t *testing.T) {
+	config := &Config{
+		Endpoint: Endpoint{
+			RequestTokenURL: "http://wrong.com/oauth/request_token",
+		},
+	}
+	token, err := config.RequestTokenPair()
+	assert.NotNil(t, err)
+	assert.Nil(t, token)
+}
+
+func TestConfigAccessTokenPair(t *testing.T) {
+	data := url.Values{}
+	data.Add("oauth_token", "access_token")
+	data.Add("oauth_token_secret", "access_secret")
+	server := newAccessTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			AccessTokenURL: server.URL,
+		},
+	}
+	requestToken := &Token{Token: "request_token", TokenSecret: "request_secret"}
+	token, err := config.AccessTokenPair(requestToken, expectedVerifier)
+	assert.Nil(t, err)
+	assert.Equal(t, &Token{Token: "access_token", TokenSecret: "access_secret"}, token)
+}
+
+func TestConfigAccessTokenPair_MissingTokenOrSecret(t *testing.T) {
+	data := url.Values{}
+	data.Add("oauth_token", "access_token")
+	server := newAccessTokenServer(t, data)
+	defer server.Close()
+
+	config := &Config{
+		Endpoint: Endpoint{
+			AccessTokenURL: server.URL,
+		},
+	}
+	requestToken := &Token{Token: "request_token", TokenSecret: "request_secret"}
+	token, err := config.AccessTokenPair(requestToken, expectedVerifier)
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Response missing oauth_token or oauth_token_secret", err.Error())
+	}
+	assert.Nil(t, token)
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	token := &Token{Token: "token", TokenSecret: "secret"}
+	src := StaticTokenSource(token)
+	got, err := src.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, token, got)
+}
+
+func TestStaticTokenSource_NilToken(t *testing.T) {
+	got, err := StaticTokenSource(nil).Token()
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Token is nil", err.Error())
+	}
+	assert.Nil(t, got)
+}
+
+func TestConfigTokenSourceClient(t *testing.T) {
+	var seen []string
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		seen = append(seen, params["oauth_token"])
+	})
+	defer server.Close()
+
+	src := &rotatingTokenSource{tokens: []*Token{
+		{Token: "token_a", TokenSecret: "secret_a"},
+		{Token: "token_b", TokenSecret: "secret_b"},
+	}}
+	client := (&Config{ConsumerKey: "consumer_key"}).TokenSourceClient(NoContext, src)
+	for i := 0; i < 3; i++ {
+		_, err := client.Get(server.URL)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []string{"token_a", "token_b", "token_a"}, seen)
+}
@@ -1,46 +1,139 @@
 package oauth1
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 // Transport is an http.RoundTripper which makes OAuth1 HTTP requests. It
-// wraps a base RoundTripper and adds an Authorization header using the
-// token from a TokenSource.
+// wraps a base RoundTripper and signs requests using the token from a
+// TokenSource, adding an Authorization header or, for Endpoints with
+// ParamsInQuery, the protocol parameters to the query.
 //
 // Transport is a low-level component, most users should use Config to create
 // an http.Client instead.
+//
+// A Transport is safe for concurrent use by multiple goroutines as long as
+// its fields are not modified and its TokenSource, TokenLookup and Routes'
+// Sources and Signers are safe for concurrent use.
 type Transport struct {
 	// Base is the base RoundTripper used to make HTTP requests. If nil, then
 	// http.DefaultTransport is used
 	Base http.RoundTripper
 
+	// Routes select how requests are signed; the first Route matching a
+	// request applies. Requests matching no Route are signed with the
+	// Transport's token, so end Routes with an unconditional Unsigned Route
+	// to sign only the routes listed before it.
+	Routes []Route
+
+	// TokenLookup, if set, selects the Token for each request not routed
+	// to a Route's Source, e.g. by host, path or headers, instead of the
+	// Transport's TokenSource.
+	TokenLookup func(*http.Request) (*Token, error)
+
+	// ExtraParams are additional protocol parameters, such as x_auth_mode,
+	// signed and sent with the protocol parameters of every request
+	ExtraParams url.Values
+
+	// Metrics receives measurements of signed requests, discarded if nil
+	Metrics Metrics
+
 	consumerKey    string
 	consumerSecret string
-	accessToken    string
-	accessSecret   string
+	secrets        SecretProvider
+	realm          string
+	source         TokenSource
+	noncer         Noncer
+	clock          func() time.Time
+	signer         Signer
+	excludeQuery   bool
+	excludeBody    bool
+	inQuery        bool
+	compress       bool
 }
 
-// RoundTrip authorizes the request with a signed OAuth1 Authorization header
-// using the credentials given.
+// RoundTrip authorizes the request with a signed OAuth1 Authorization header,
+// or signed protocol parameters in the query for Endpoints with
+// ParamsInQuery, using the credentials given. The request is not modified:
+// the header or query is set on a clone and a form body, which must be read
+// to be signed, is replaced on the clone only, along with a GetBody so that
+// net/http can replay it. If the request has a GetBody, the body is taken
+// from it and the request's own body is closed unread. As the RoundTripper
+// contract requires, the request body is closed on errors too.
 func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req2 := cloneRequest(req)
-	params, err := prepareParams(req, t.consumerKey)
+	r := route(t.Routes, req)
+	if r != nil && r.Unsigned {
+		return t.base().RoundTrip(req)
+	}
+	token, err := t.token(req, r)
 	if err != nil {
+		closeBody(req)
 		return nil, err
 	}
-	params.Add("oauth_token", t.accessToken)
-	signer := Signer{nonce(), time.Now()}
-	signature, err := signer.Sign(t.consumerSecret, t.accessSecret, req, params)
+	req2 := cloneRequest(req)
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody != nil {
+		// sign and send a fresh body, closing req.Body unread
+		req2.Body, err = req.GetBody()
+		closeBody(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	oauthParams, tokenSecret := tokenParams(token)
+	st := stamp{newNonce(t.noncer), signingTime(t.clock)}
+	s := t.signing()
+	if r != nil && r.Signer != nil {
+		s.signer = r.Signer
+	}
+	err = s.authorize(req2, tokenSecret, oauthParams, st)
+	t.metrics().RequestSigned(req.URL.Host, err)
 	if err != nil {
+		closeBody(req2)
 		return nil, err
 	}
-	params.Add("oauth_signature", signature)
-	req2.Header.Add("Authorization", formatOAuthHeader(params))
+	if t.compress && hasBody(req2) && req2.Header.Get("Content-Encoding") == "" {
+		if t.excludeBody {
+			streamCompressedBody(req2, req2.Body)
+		} else if err := compressBody(req2, req2.Body); err != nil {
+			return nil, err
+		}
+	}
 	return t.base().RoundTrip(req2)
 }
 
+// token returns the Token to sign req with, from the Source of the Route r
+// matching req if any, else from the TokenLookup or TokenSource. Sources
+// which are ContextTokenSources are passed the context of req.
+func (t *Transport) token(req *http.Request, r *Route) (*Token, error) {
+	if r != nil && r.Source != nil {
+		return sourceToken(req.Context(), r.Source)
+	}
+	if t.TokenLookup != nil {
+		return t.TokenLookup(req)
+	}
+	if t.source == nil {
+		return nil, errors.New("oauth1: Transport's source is nil")
+	}
+	return sourceToken(req.Context(), t.source)
+}
+
+func (t *Transport) signing() signing {
+	return signing{
+		consumerKey:    t.consumerKey,
+		consumerSecret: t.consumerSecret,
+		secrets:        t.secrets,
+		realm:          t.realm,
+		excludeQuery:   t.excludeQuery,
+		excludeBody:    t.excludeBody,
+		extraParams:    t.ExtraParams,
+		inQuery:        t.inQuery,
+		signer:         t.signer,
+	}
+}
+
 func (t *Transport) base() http.RoundTripper {
 	if t.Base != nil {
 		return t.Base
@@ -48,6 +141,18 @@ func (t *Transport) base() http.RoundTripper {
 	return http.DefaultTransport
 }
 
+// hasBody reports whether req has a body which is not known to be empty.
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0
+}
+
+// closeBody closes the body of req, if any.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
 // cloneRequest returns a clone of the given *http.Request with a shallow
 // copy of struct fields and a deep copy of the Header map.
 func cloneRequest(req *http.Request) *http.Request {
@@ -1,11 +1,18 @@
 package oauth1
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -30,8 +37,7 @@ func TestTransport(t *testing.T) {
 	tr := &Transport{
 		consumerKey:    expectedConsumerKey,
 		consumerSecret: "consumer_secret",
-		accessToken:    expectedToken,
-		accessSecret:   "some_secret",
+		source:         StaticTokenSource(&Token{Token: expectedToken, TokenSecret: "some_secret"}),
 	}
 	client := &http.Client{Transport: tr}
 
@@ -71,6 +77,194 @@ func parseOAuthParamsOrFail(t *testing.T, authHeader string) map[string]string {
 	return params
 }
 
+// formSignature returns the oauth_signature s makes for a POST of form to the
+// URL req was received at, with the oauth_token, nonce and timestamp of the
+// received params.
+func formSignature(t *testing.T, s signing, req *http.Request, form url.Values, tokenSecret string, params map[string]string) string {
+	signed, err := http.NewRequest("POST", "http://"+req.Host+req.URL.Path, strings.NewReader(form.Encode()))
+	assert.Nil(t, err)
+	signed.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header, err := s.authorizationHeader(signed, tokenSecret,
+		url.Values{"oauth_token": {params["oauth_token"]}},
+		stamp{params["oauth_nonce"], time.Unix(mustParseInt(t, params["oauth_timestamp"]), 0)})
+	assert.Nil(t, err)
+	return parseOAuthParamsOrFail(t, header)["oauth_signature"]
+}
+
 func newMockServer(handler func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(handler))
 }
+
+func TestTransport_nilSource(t *testing.T) {
+	tr := &Transport{consumerKey: "consumer_key"}
+	req, err := http.NewRequest("GET", "http://example.com", nil)
+	assert.Nil(t, err)
+	_, err = tr.RoundTrip(req)
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Transport's source is nil", err.Error())
+	}
+}
+
+func TestTransport_TokenLookup(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, req.URL.Query().Get("user"), params["oauth_token"])
+	})
+	defer server.Close()
+
+	lookup := func(req *http.Request) (*Token, error) {
+		user := req.URL.Query().Get("user")
+		return &Token{Token: user, TokenSecret: user + "_secret"}, nil
+	}
+	client := (&Config{ConsumerKey: "consumer_key"}).LookupClient(NoContext, lookup)
+	for _, user := range []string{"alice", "bob"} {
+		_, err := client.Get(server.URL + "?user=" + user)
+		assert.Nil(t, err)
+	}
+}
+
+func TestTransport_TokenLookupError(t *testing.T) {
+	lookupErr := errors.New("unknown host")
+	tr := &Transport{
+		TokenLookup: func(req *http.Request) (*Token, error) {
+			return nil, lookupErr
+		},
+	}
+	body := &closeRecorder{ReadCloser: ioutil.NopCloser(strings.NewReader("status=hello"))}
+	req, err := http.NewRequest("POST", "http://example.com", body)
+	assert.Nil(t, err)
+	_, err = tr.RoundTrip(req)
+	assert.Equal(t, lookupErr, err)
+	assert.True(t, body.closed)
+}
+
+// closeRecorder records whether it was closed.
+type closeRecorder struct {
+	io.ReadCloser
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return c.ReadCloser.Close()
+}
+
+func TestTransport_doesNotModifyRequest(t *testing.T) {
+	var tr http.RoundTripper = &Transport{
+		consumerKey: "consumer_key",
+		source:      StaticTokenSource(&Token{Token: "access_token", TokenSecret: "access_secret"}),
+		Base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			b, err := ioutil.ReadAll(req.Body)
+			assert.Nil(t, err)
+			assert.Equal(t, "status=hello", string(b))
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+	req, err := http.NewRequest("POST", "http://example.com", strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body := &closeRecorder{ReadCloser: req.Body}
+	req.Body = body
+
+	_, err = tr.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.True(t, body == req.Body)
+	assert.Empty(t, req.Header.Get("Authorization"))
+	// the body of req is closed unread
+	assert.True(t, body.closed)
+	b, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "status=hello", string(b))
+}
+
+func TestTransport_inQueryDoesNotModifyRequest(t *testing.T) {
+	var tr http.RoundTripper = &Transport{
+		consumerKey: "consumer_key",
+		source:      StaticTokenSource(&Token{Token: "access_token", TokenSecret: "access_secret"}),
+		inQuery:     true,
+		Base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			assert.Equal(t, "5", query.Get("count"))
+			assert.Equal(t, "access_token", query.Get("oauth_token"))
+			assert.NotEmpty(t, query.Get("oauth_signature"))
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+	req, err := http.NewRequest("GET", "http://example.com/feed?count=5", nil)
+	assert.Nil(t, err)
+
+	_, err = tr.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "count=5", req.URL.RawQuery)
+}
+
+func TestTransport_concurrentFormRequests(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.NotContains(t, params, "n")
+		// each request is signed with its own body
+		assert.Nil(t, req.ParseForm())
+		expected := formSignature(t, signing{consumerKey: "consumer_key"}, req, req.PostForm, "secret", params)
+		assert.Equal(t, expected, params["oauth_signature"])
+	})
+	defer server.Close()
+
+	client := (&Config{ConsumerKey: "consumer_key"}).Client(NoContext, "token", "secret")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := client.PostForm(server.URL, url.Values{"n": {strconv.Itoa(i)}})
+			if assert.Nil(t, err) {
+				res.Body.Close()
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestTransport_excludeBodyStreams(t *testing.T) {
+	body := ioutil.NopCloser(strings.NewReader("status=hello"))
+	tr := &Transport{
+		consumerKey: "consumer_key",
+		source:      StaticTokenSource(&Token{Token: "access_token", TokenSecret: "access_secret"}),
+		excludeBody: true,
+		Base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			assert.True(t, body == req.Body)
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+	req, err := http.NewRequest("PUT", "http://example.com/upload", body)
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = tr.RoundTrip(req)
+	assert.Nil(t, err)
+}
+
+func TestTransport_setsGetBody(t *testing.T) {
+	tr := &Transport{
+		consumerKey: "consumer_key",
+		source:      StaticTokenSource(&Token{Token: "access_token", TokenSecret: "access_secret"}),
+		Base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, int64(len("status=hello")), req.ContentLength)
+			first, err := ioutil.ReadAll(req.Body)
+			assert.Nil(t, err)
+			if assert.NotNil(t, req.GetBody) {
+				// replay, as net/http does after a connection reset
+				body, err := req.GetBody()
+				assert.Nil(t, err)
+				replayed, err := ioutil.ReadAll(body)
+				assert.Nil(t, err)
+				assert.Equal(t, first, replayed)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}),
+	}
+	req, err := http.NewRequest("POST", "http://example.com", strings.NewReader("status=hello"))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.GetBody = nil
+	_, err = tr.RoundTrip(req)
+	assert.Nil(t, err)
+}
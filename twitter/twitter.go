@@ -11,6 +11,7 @@ import (
 var AuthenticateEndpoint = oauth1.Endpoint{
 	RequestTokenURL: "https://api.twitter.com/oauth/request_token",
 	AuthorizeURL:    "https://api.twitter.com/oauth/authenticate",
+	AuthenticateURL: "https://api.twitter.com/oauth/authenticate",
 	AccessTokenURL:  "https://api.twitter.com/oauth/access_token",
 }
 
@@ -18,8 +19,11 @@ var AuthenticateEndpoint = oauth1.Endpoint{
 // oauth/authorize AuthorizeURL redirect. Note that this requires users who
 // have granted access previously, to re-grant access at AuthorizeURL.
 // Prefer AuthenticateEndpoint over AuthorizeEndpoint if you are unsure.
+// Config.AuthenticationURL uses its oauth/authenticate AuthenticateURL, so
+// one Config can send users to either page.
 var AuthorizeEndpoint = oauth1.Endpoint{
 	RequestTokenURL: "https://api.twitter.com/oauth/request_token",
 	AuthorizeURL:    "https://api.twitter.com/oauth/authorize",
+	AuthenticateURL: "https://api.twitter.com/oauth/authenticate",
 	AccessTokenURL:  "https://api.twitter.com/oauth/access_token",
 }
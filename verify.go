@@ -0,0 +1,370 @@
+package oauth1
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimestampWindow is how far the oauth_timestamp of a verified
+// request may lie from the current time when a Verifier has no
+// TimestampWindow.
+const DefaultTimestampWindow = 5 * time.Minute
+
+// Verifier verifies the signatures of requests received by a provider or a
+// resource server protected with OAuth1, reconstructing the signature base
+// string from the request the way a consumer signs it. HMAC-SHA1,
+// HMAC-SHA256 and PLAINTEXT signatures are supported, and RSA-SHA1 and
+// RSA-SHA256 signatures of consumers with a PublicKey. Signatures are
+// compared in constant time and the expected signature is never exposed.
+type Verifier struct {
+	// Consumers looks up the consumers signing requests
+	Consumers ConsumerStore
+
+	// ConsumerSecret returns the secret of a consumer key, or an error if
+	// the consumer is unknown. It is used if Consumers is nil.
+	ConsumerSecret func(consumerKey string) (string, error)
+
+	// TokenSecret returns the secret of a token, or an error if the token
+	// is unknown or revoked. Requests without an oauth_token, as sent by
+	// 2-legged consumers, are verified with an empty token secret. If nil,
+	// only requests without an oauth_token are accepted.
+	TokenSecret func(token string) (string, error)
+
+	// RequestURL returns the URL the consumer sent req to, by default
+	// req.URL completed with req.Host and the scheme of the connection.
+	// Set it behind proxies which rewrite the host or terminate TLS.
+	RequestURL func(req *http.Request) *url.URL
+
+	// TimestampWindow is the maximum clock skew between consumer and
+	// verifier: requests with an oauth_timestamp further from the current
+	// time are refused with timestamp_refused, reporting the acceptable
+	// range. DefaultTimestampWindow if zero.
+	TimestampWindow time.Duration
+
+	// BodyHash selects how the oauth_body_hash of requests is checked,
+	// BodyHashOptional by default
+	BodyHash BodyHashPolicy
+
+	// Nonces remembers the nonces of verified requests to reject replays
+	// with nonce_used. If nil, nonces are not checked for reuse.
+	Nonces NonceStore
+
+	// Clock returns the current time, time.Now if nil
+	Clock func() time.Time
+}
+
+// VerificationError reports why a request failed verification as the
+// Problem to report to the consumer, per the Problem Reporting extension.
+type VerificationError struct {
+	Problem Problem
+
+	// Err is the cause, such as an error of a secret lookup, if any
+	Err error
+}
+
+func (e *VerificationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("oauth1: Request verification failed: %s: %v", e.Problem.Problem, e.Err)
+	}
+	return fmt.Sprintf("oauth1: Request verification failed: %s", e.Problem.Problem)
+}
+
+// Unwrap returns the cause of e.
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+// Verify verifies the signature, nonce and timestamp of req using the
+// secrets of its consumer key and token as returned by consumerSecret and
+// tokenSecret. See Verifier.
+func Verify(req *http.Request, consumerSecret, tokenSecret func(string) (string, error)) error {
+	v := &Verifier{ConsumerSecret: consumerSecret, TokenSecret: tokenSecret}
+	_, err := v.Verify(req)
+	return err
+}
+
+// Verify verifies the signature, nonce and timestamp of req and returns its
+// protocol parameters, such as oauth_consumer_key and oauth_token. The
+// protocol parameters may be transmitted in an OAuth Authorization header,
+// the form body or the query, but only in one of them; requests mixing
+// transmission methods are rejected with parameter_rejected. See RFC 5849
+// 3.5. A form body is read and replaced with an equivalent reader. Errors
+// are *VerificationErrors, except for errors of the NonceStore.
+func (v *Verifier) Verify(req *http.Request) (url.Values, error) {
+	params, err := signing{}.requestParams(req)
+	if err != nil {
+		return nil, verificationError("parameter_rejected", err)
+	}
+	transmissions := make(map[string]bool)
+	oauthParams := make(url.Values)
+	if header := req.Header.Get("Authorization"); isOAuthHeader(header) {
+		if oauthParams, err = ParseOAuthHeader(header); err != nil {
+			return nil, verificationError("parameter_rejected", err)
+		}
+		transmissions["header"] = true
+	}
+	query := req.URL.Query()
+	for name, values := range params {
+		if !strings.HasPrefix(name, "oauth_") {
+			continue
+		}
+		if len(query[name]) > 0 {
+			transmissions["query"] = true
+		}
+		if len(values) > len(query[name]) {
+			transmissions["body"] = true
+		}
+	}
+	if len(transmissions) > 1 {
+		e := verificationError("parameter_rejected", nil)
+		e.Problem.Advice = "OAuth parameters must be transmitted in exactly one of the Authorization header, the form body or the query"
+		return nil, e
+	}
+	if !transmissions["header"] {
+		oauthParams = splitOAuthParams(params)
+	}
+	return v.verify(req, oauthParams, params)
+}
+
+// isOAuthHeader reports whether an Authorization header uses the OAuth
+// scheme.
+func isOAuthHeader(header string) bool {
+	header = strings.TrimSpace(header)
+	const scheme = "OAuth"
+	return len(header) >= len(scheme) && strings.EqualFold(header[:len(scheme)], scheme) &&
+		(len(header) == len(scheme) || isSpace(header[len(scheme)]))
+}
+
+// splitOAuthParams removes the oauth_* parameters from params and returns
+// them.
+func splitOAuthParams(params url.Values) url.Values {
+	oauthParams := make(url.Values)
+	for name, values := range params {
+		if strings.HasPrefix(name, "oauth_") {
+			oauthParams[name] = values
+			delete(params, name)
+		}
+	}
+	return oauthParams
+}
+
+// verify verifies the signature, nonce and timestamp of req carrying the
+// protocol parameters oauthParams and the other parameters params, which
+// are signed along with oauthParams.
+func (v *Verifier) verify(req *http.Request, oauthParams, params url.Values) (url.Values, error) {
+	method := oauthParams.Get("oauth_signature_method")
+	required := []string{"oauth_consumer_key", "oauth_signature_method", "oauth_signature"}
+	if method != "PLAINTEXT" {
+		required = append(required, "oauth_timestamp", "oauth_nonce")
+	}
+	var absent []string
+	for _, name := range required {
+		if oauthParams.Get(name) == "" {
+			absent = append(absent, name)
+		}
+	}
+	if len(absent) > 0 {
+		e := verificationError("parameter_absent", nil)
+		e.Problem.ParametersAbsent = absent
+		return nil, e
+	}
+	if version, ok := oauthParams["oauth_version"]; ok && version[0] != "1.0" {
+		return nil, verificationError("version_rejected", nil)
+	}
+	if err := v.verifyTimestamp(oauthParams); err != nil {
+		return nil, err
+	}
+
+	consumer, err := v.consumer(oauthParams.Get("oauth_consumer_key"))
+	if err != nil {
+		return nil, err
+	}
+	tokenSecret := ""
+	if token := oauthParams.Get("oauth_token"); token != "" {
+		if v.TokenSecret == nil {
+			return nil, verificationError("token_rejected", nil)
+		}
+		if tokenSecret, err = v.TokenSecret(token); err != nil {
+			return nil, verificationError("token_rejected", err)
+		}
+	}
+
+	switch method {
+	case "HMAC-SHA1", "HMAC-SHA256", "PLAINTEXT":
+	case "RSA-SHA1", "RSA-SHA256":
+		if consumer.PublicKey == nil {
+			return nil, verificationError("signature_method_rejected", nil)
+		}
+	default:
+		return nil, verificationError("signature_method_rejected", nil)
+	}
+	for name, values := range oauthParams {
+		if name == "realm" || name == "oauth_signature" {
+			continue
+		}
+		params[name] = append(params[name], values...)
+	}
+	base := signatureBase(signatureHTTPMethod(req), v.requestURL(req), params)
+	if err := verifySignature(method, consumer, tokenSecret, base, oauthParams.Get("oauth_signature")); err != nil {
+		return nil, err
+	}
+	if err := v.checkBodyHash(req, oauthParams); err != nil {
+		return nil, err
+	}
+	if err := v.checkNonce(oauthParams); err != nil {
+		return nil, err
+	}
+	delete(oauthParams, "realm")
+	return oauthParams, nil
+}
+
+// verifySignature verifies that signature is the signature of base made
+// with the named signature method by consumer and the holder of
+// tokenSecret. RSA signatures are verified with the PublicKey of consumer.
+func verifySignature(method string, consumer *Consumer, tokenSecret, base, signature string) error {
+	var hash crypto.Hash
+	switch method {
+	case "RSA-SHA1":
+		hash = crypto.SHA1
+	case "RSA-SHA256":
+		hash = crypto.SHA256
+	default:
+		key := percentEncode(consumer.Secret) + "&" + percentEncode(tokenSecret)
+		expected, err := methodSigner(method).Sign(key, base)
+		if err != nil {
+			return verificationError("signature_invalid", err)
+		}
+		if !constantTimeEqual(expected, signature) {
+			return verificationError("signature_invalid", nil)
+		}
+		return nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return verificationError("signature_invalid", err)
+	}
+	h := hash.New()
+	h.Write([]byte(base))
+	if err := rsa.VerifyPKCS1v15(consumer.PublicKey, hash, h.Sum(nil), decoded); err != nil {
+		return verificationError("signature_invalid", err)
+	}
+	return nil
+}
+
+// constantTimeEqual reports whether the signatures, hashes or verifiers a
+// and b are equal, taking time independent of their content so a forger
+// cannot learn the expected value byte by byte from response times. All
+// comparisons of secret derived values during verification use it.
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// consumer looks up the consumer of consumerKey, failing unknown, refused
+// and disabled consumers.
+func (v *Verifier) consumer(consumerKey string) (*Consumer, error) {
+	consumers := v.Consumers
+	if consumers == nil {
+		consumers = ConsumerSecretStore(v.ConsumerSecret)
+	}
+	consumer, err := consumers.Consumer(consumerKey)
+	switch {
+	case errors.Is(err, ErrConsumerRefused):
+		return nil, verificationError("consumer_key_refused", err)
+	case err != nil:
+		return nil, verificationError("consumer_key_unknown", err)
+	case consumer.Disabled:
+		return nil, verificationError("consumer_key_rejected", nil)
+	}
+	return consumer, nil
+}
+
+// verifyTimestamp checks that the oauth_timestamp of oauthParams, if any,
+// lies within the accepted window around the current time.
+func (v *Verifier) verifyTimestamp(oauthParams url.Values) error {
+	raw := oauthParams.Get("oauth_timestamp")
+	if raw == "" {
+		return nil
+	}
+	timestamp, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return verificationError("timestamp_refused", err)
+	}
+	now, window := signingTime(v.Clock), v.timestampWindow()
+	min, max := now.Add(-window).Unix(), now.Add(window).Unix()
+	if timestamp < min || timestamp > max {
+		e := verificationError("timestamp_refused", nil)
+		e.Problem.AcceptableTimestamps = [2]int64{min, max}
+		return e
+	}
+	return nil
+}
+
+func (v *Verifier) timestampWindow() time.Duration {
+	if v.TimestampWindow > 0 {
+		return v.TimestampWindow
+	}
+	return DefaultTimestampWindow
+}
+
+// checkNonce rejects the oauth_nonce of oauthParams if it was used before
+// with the same consumer key, token and timestamp, and otherwise remembers
+// it for as long as the timestamp is accepted. Errors of the NonceStore are
+// returned as is, being server errors rather than problems of the request.
+func (v *Verifier) checkNonce(oauthParams url.Values) error {
+	nonce := oauthParams.Get("oauth_nonce")
+	if v.Nonces == nil || nonce == "" {
+		return nil
+	}
+	timestamp := oauthParams.Get("oauth_timestamp")
+	key := strings.Join([]string{
+		percentEncode(oauthParams.Get("oauth_consumer_key")),
+		percentEncode(oauthParams.Get("oauth_token")),
+		percentEncode(timestamp),
+		percentEncode(nonce),
+	}, "&")
+	ttl := 2 * v.timestampWindow()
+	if unix, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		ttl = time.Unix(unix, 0).Add(v.timestampWindow()).Sub(signingTime(v.Clock))
+	}
+	unused, err := v.Nonces.Use(key, ttl)
+	if err != nil {
+		return err
+	}
+	if !unused {
+		return verificationError("nonce_used", nil)
+	}
+	return nil
+}
+
+// requestURL returns the absolute URL req was sent to.
+func (v *Verifier) requestURL(req *http.Request) *url.URL {
+	if v.RequestURL != nil {
+		return v.RequestURL(req)
+	}
+	u := *req.URL
+	if u.Host == "" {
+		u.Host = req.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if req.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return &u
+}
+
+// verificationError returns a VerificationError reporting problem, caused
+// by err if non-nil.
+func verificationError(problem string, err error) *VerificationError {
+	return &VerificationError{Problem: Problem{Problem: problem}, Err: err}
+}
@@ -0,0 +1,400 @@
+package oauth1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnknown = errors.New("unknown")
+
+func lookupSecret(secrets map[string]string) func(string) (string, error) {
+	return func(key string) (string, error) {
+		secret, ok := secrets[key]
+		if !ok {
+			return "", errUnknown
+		}
+		return secret, nil
+	}
+}
+
+func newTestVerifier() *Verifier {
+	return &Verifier{
+		ConsumerSecret: lookupSecret(map[string]string{"consumer_key": "consumer secret"}),
+		TokenSecret:    lookupSecret(map[string]string{"token": "token&secret"}),
+	}
+}
+
+func TestVerifier_Client(t *testing.T) {
+	v := newTestVerifier()
+	var verifyErr error
+	var verified url.Values
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		verified, verifyErr = v.Verify(req)
+		// the body remains readable
+		assert.Nil(t, req.ParseForm())
+	})
+	defer server.Close()
+
+	for _, signer := range []Signer{HMACSigner{}, HMACSHA256Signer{}, PlaintextSigner{}} {
+		config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret", Signer: signer}
+		client := config.Client(NoContext, "token", "token&secret")
+
+		_, err := client.Get(server.URL + "/1/feed?count=5&q=a%20b%2Fc&q=%E2%9C%93")
+		assert.Nil(t, err)
+		assert.Nil(t, verifyErr, signer.Name())
+		assert.Equal(t, "consumer_key", verified.Get("oauth_consumer_key"))
+		assert.Equal(t, "token", verified.Get("oauth_token"))
+
+		_, err = client.PostForm(server.URL+"/1/statuses/update", url.Values{"status": {"hello, world*"}})
+		assert.Nil(t, err)
+		assert.Nil(t, verifyErr, signer.Name())
+	}
+}
+
+func TestVerifier_ParamsInQuery(t *testing.T) {
+	v := newTestVerifier()
+	var verifyErr error
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Empty(t, req.Header.Get("Authorization"))
+		assert.NotEmpty(t, req.URL.Query().Get("oauth_signature"))
+		_, verifyErr = v.Verify(req)
+	})
+	defer server.Close()
+
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret", Endpoint: Endpoint{ParamsInQuery: true}}
+	client := config.Client(NoContext, "token", "token&secret")
+	_, err := client.Get(server.URL + "/1/feed?count=5")
+	assert.Nil(t, err)
+	assert.Nil(t, verifyErr)
+}
+
+func TestVerifier_TwoLegged(t *testing.T) {
+	v := &Verifier{ConsumerSecret: lookupSecret(map[string]string{"consumer_key": "consumer_secret"})}
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer_secret"}
+	req, err := http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, config.SignRequest(req, nil))
+	_, err = v.Verify(req)
+	assert.Nil(t, err)
+
+	// tokens are rejected without a TokenSecret lookup
+	req, err = http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, config.SignRequest(req, &Token{Token: "token", TokenSecret: "secret"}))
+	_, err = v.Verify(req)
+	assertProblem(t, err, "token_rejected")
+}
+
+func assertProblem(t *testing.T, err error, problem string) *VerificationError {
+	var verr *VerificationError
+	if assert.True(t, errors.As(err, &verr), "%v", err) {
+		assert.Equal(t, problem, verr.Problem.Problem)
+	}
+	return verr
+}
+
+// signedRequest returns a GET request to rawurl signed with config and token.
+func signedRequest(t *testing.T, config *Config, token *Token, rawurl string) *http.Request {
+	req, err := http.NewRequest("GET", rawurl, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, config.SignRequest(req, token))
+	return req
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	token := &Token{Token: "token", TokenSecret: "token&secret"}
+	v := newTestVerifier()
+
+	// tampered query
+	req := signedRequest(t, config, token, "https://api.example.com/1/feed?count=5")
+	req.URL.RawQuery = "count=500"
+	_, err := v.Verify(req)
+	assertProblem(t, err, "signature_invalid")
+
+	// wrong token secret
+	req = signedRequest(t, config, &Token{Token: "token", TokenSecret: "guess"}, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	assertProblem(t, err, "signature_invalid")
+
+	// unknown consumer
+	req = signedRequest(t, &Config{ConsumerKey: "other_key"}, token, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	verr := assertProblem(t, err, "consumer_key_unknown")
+	assert.True(t, errors.Is(verr, errUnknown))
+
+	// unknown token
+	req = signedRequest(t, config, &Token{Token: "revoked"}, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	assertProblem(t, err, "token_rejected")
+
+	// stale timestamp
+	stale := config.Clone()
+	stale.Clock = func() time.Time { return time.Now().Add(-time.Hour) }
+	req = signedRequest(t, stale, token, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	verr = assertProblem(t, err, "timestamp_refused")
+	assert.NotZero(t, verr.Problem.AcceptableTimestamps[0])
+
+	// unsupported signature method
+	rsa := config.Clone()
+	rsa.Signer = RSASigner{PrivateKey: newTestRSAKey(t)}
+	req = signedRequest(t, rsa, token, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	assertProblem(t, err, "signature_method_rejected")
+}
+
+func TestVerifier_MissingParameters(t *testing.T) {
+	v := newTestVerifier()
+	req, err := http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	_, err = v.Verify(req)
+	verr := assertProblem(t, err, "parameter_absent")
+	assert.Equal(t, []string{"oauth_consumer_key", "oauth_signature_method", "oauth_signature", "oauth_timestamp", "oauth_nonce"}, verr.Problem.ParametersAbsent)
+
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="consumer_key", oauth_signature_method="HMAC-SHA1", oauth_signature="abc", oauth_timestamp="1"`)
+	_, err = v.Verify(req)
+	verr = assertProblem(t, err, "parameter_absent")
+	assert.Equal(t, []string{"oauth_nonce"}, verr.Problem.ParametersAbsent)
+
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="consumer_key" oauth_signature="abc"`)
+	_, err = v.Verify(req)
+	assertProblem(t, err, "parameter_rejected")
+}
+
+func TestVerifier_VersionRejected(t *testing.T) {
+	v := newTestVerifier()
+	req, err := http.NewRequest("GET", "https://api.example.com/1/feed", nil)
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="consumer_key", oauth_signature_method="PLAINTEXT", oauth_signature="consumer%2520secret%26", oauth_version="2.0"`)
+	_, err = v.Verify(req)
+	assertProblem(t, err, "version_rejected")
+
+	// PLAINTEXT needs neither timestamp nor nonce
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="consumer_key", oauth_signature_method="PLAINTEXT", oauth_signature="consumer%2520secret%26"`)
+	_, err = v.Verify(req)
+	assert.Nil(t, err)
+}
+
+func TestVerifier_RequestURL(t *testing.T) {
+	// a proxy terminating TLS forwards the request over plain HTTP
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	token := &Token{Token: "token", TokenSecret: "token&secret"}
+	signed := signedRequest(t, config, token, "https://api.example.com/1/feed")
+	req, err := http.NewRequest("GET", "/1/feed", nil)
+	assert.Nil(t, err)
+	req.Host = "backend:8080"
+	req.Header.Set("Authorization", signed.Header.Get("Authorization"))
+
+	v := newTestVerifier()
+	_, err = v.Verify(req)
+	assertProblem(t, err, "signature_invalid")
+
+	v.RequestURL = func(req *http.Request) *url.URL {
+		u := *req.URL
+		u.Scheme, u.Host = "https", "api.example.com"
+		return &u
+	}
+	_, err = v.Verify(req)
+	assert.Nil(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	req := signedRequest(t, config, &Token{Token: "token", TokenSecret: "token&secret"}, "https://api.example.com/1/feed")
+	assert.Nil(t, Verify(req,
+		lookupSecret(map[string]string{"consumer_key": "consumer secret"}),
+		lookupSecret(map[string]string{"token": "token&secret"})))
+	err := Verify(req, lookupSecret(nil), lookupSecret(nil))
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "oauth1: Request verification failed: consumer_key_unknown"))
+	}
+}
+
+func TestVerifier_Nonces(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret", Noncer: FixedNoncer("nonce")}
+	token := &Token{Token: "token", TokenSecret: "token&secret"}
+	v := newTestVerifier()
+	v.Nonces = NewMemoryNonceStore(0)
+
+	req := signedRequest(t, config, token, "https://api.example.com/1/feed")
+	_, err := v.Verify(req)
+	assert.Nil(t, err)
+	_, err = v.Verify(req)
+	assertProblem(t, err, "nonce_used")
+
+	// the nonce may be reused by other consumers or tokens
+	other := signedRequest(t, config, &Token{Token: "other", TokenSecret: "secret"}, "https://api.example.com/1/feed")
+	v.TokenSecret = lookupSecret(map[string]string{"other": "secret"})
+	_, err = v.Verify(other)
+	assert.Nil(t, err)
+}
+
+// failingNonceStore is a NonceStore which is unavailable.
+type failingNonceStore struct{}
+
+func (failingNonceStore) Use(key string, ttl time.Duration) (bool, error) {
+	return false, errUnknown
+}
+
+func TestVerifier_NonceStoreError(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	v := newTestVerifier()
+	v.Nonces = failingNonceStore{}
+	req := signedRequest(t, config, &Token{Token: "token", TokenSecret: "token&secret"}, "https://api.example.com/1/feed")
+	_, err := v.Verify(req)
+	assert.Equal(t, errUnknown, err)
+}
+
+func TestVerifier_TimestampWindow(t *testing.T) {
+	now := time.Unix(1318467427, 0)
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	config.Clock = func() time.Time { return now.Add(-2 * time.Minute) }
+	token := &Token{Token: "token", TokenSecret: "token&secret"}
+	v := newTestVerifier()
+	v.Clock = func() time.Time { return now }
+
+	req := signedRequest(t, config, token, "https://api.example.com/1/feed")
+	_, err := v.Verify(req)
+	assert.Nil(t, err)
+
+	v.TimestampWindow = time.Minute
+	req = signedRequest(t, config, token, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	verr := assertProblem(t, err, "timestamp_refused")
+	assert.Equal(t, [2]int64{1318467367, 1318467487}, verr.Problem.AcceptableTimestamps)
+	assert.Equal(t, "1318467367-1318467487", verr.Problem.Values().Get("oauth_acceptable_timestamps"))
+}
+
+type consumerMap map[string]*Consumer
+
+func (m consumerMap) Consumer(consumerKey string) (*Consumer, error) {
+	if consumerKey == "busy_key" {
+		return nil, ErrConsumerRefused
+	}
+	consumer, ok := m[consumerKey]
+	if !ok {
+		return nil, errUnknown
+	}
+	return consumer, nil
+}
+
+func TestVerifier_Consumers(t *testing.T) {
+	v := &Verifier{Consumers: consumerMap{
+		"consumer_key": {Key: "consumer_key", Secret: "consumer secret"},
+		"banned_key":   {Key: "banned_key", Secret: "consumer secret", Disabled: true},
+	}}
+	req := signedRequest(t, &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}, nil, "https://api.example.com/1/feed")
+	_, err := v.Verify(req)
+	assert.Nil(t, err)
+
+	req = signedRequest(t, &Config{ConsumerKey: "banned_key", ConsumerSecret: "consumer secret"}, nil, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	assertProblem(t, err, "consumer_key_rejected")
+
+	req = signedRequest(t, &Config{ConsumerKey: "busy_key", ConsumerSecret: "consumer secret"}, nil, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	verr := assertProblem(t, err, "consumer_key_refused")
+	assert.True(t, errors.Is(verr, ErrConsumerRefused))
+
+	req = signedRequest(t, &Config{ConsumerKey: "other_key"}, nil, "https://api.example.com/1/feed")
+	_, err = v.Verify(req)
+	assertProblem(t, err, "consumer_key_unknown")
+}
+
+// moveOAuthParams returns the protocol parameters of the Authorization
+// header of req, which is removed.
+func moveOAuthParams(t *testing.T, req *http.Request) url.Values {
+	oauthParams, err := ParseOAuthHeader(req.Header.Get("Authorization"))
+	assert.Nil(t, err)
+	req.Header.Del("Authorization")
+	return oauthParams
+}
+
+func TestVerifier_QueryParameters(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	token := &Token{Token: "token", TokenSecret: "token&secret"}
+	v := newTestVerifier()
+
+	req := signedRequest(t, config, token, "https://api.example.com/1/feed?count=5")
+	query := req.URL.Query()
+	for name, values := range moveOAuthParams(t, req) {
+		query[name] = values
+	}
+	req.URL.RawQuery = query.Encode()
+	params, err := v.Verify(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "token", params.Get("oauth_token"))
+
+	// header parameters may not be mixed with query parameters
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="consumer_key"`)
+	_, err = v.Verify(req)
+	assertProblem(t, err, "parameter_rejected")
+
+	// other authorization schemes are ignored
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	_, err = v.Verify(req)
+	assert.Nil(t, err)
+}
+
+func TestVerifier_BodyParameters(t *testing.T) {
+	config := &Config{ConsumerKey: "consumer_key", ConsumerSecret: "consumer secret"}
+	token := &Token{Token: "token", TokenSecret: "token&secret"}
+	v := newTestVerifier()
+	newRequest := func(form url.Values) *http.Request {
+		req, err := http.NewRequest("POST", "https://api.example.com/1/statuses/update?trim=1", strings.NewReader(form.Encode()))
+		assert.Nil(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	form := url.Values{"status": {"hello"}}
+	signed := newRequest(form)
+	assert.Nil(t, config.SignRequest(signed, token))
+	for name, values := range moveOAuthParams(t, signed) {
+		form[name] = values
+	}
+	_, err := v.Verify(newRequest(form))
+	assert.Nil(t, err)
+
+	// body parameters may not be mixed with query parameters
+	req := newRequest(form)
+	req.URL.RawQuery += "&oauth_version=1.0"
+	_, err = v.Verify(req)
+	assertProblem(t, err, "parameter_rejected")
+}
+
+func TestVerifier_RSA(t *testing.T) {
+	key := newTestRSAKey(t)
+	v := &Verifier{Consumers: consumerMap{
+		"consumer_key": {Key: "consumer_key", PublicKey: &key.PublicKey},
+	}}
+	for _, signer := range []Signer{RSASigner{PrivateKey: key}, RSASHA256Signer{PrivateKey: key}} {
+		config := &Config{ConsumerKey: "consumer_key", Signer: signer}
+		req := signedRequest(t, config, nil, "https://api.example.com/1/feed?count=5")
+		_, err := v.Verify(req)
+		assert.Nil(t, err, signer.Name())
+
+		req.URL.RawQuery = "count=500"
+		_, err = v.Verify(req)
+		assertProblem(t, err, "signature_invalid")
+	}
+
+	config := &Config{ConsumerKey: "consumer_key", Signer: RSASigner{PrivateKey: newTestRSAKey(t)}}
+	_, err := v.Verify(signedRequest(t, config, nil, "https://api.example.com/1/feed"))
+	assertProblem(t, err, "signature_invalid")
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	assert.True(t, constantTimeEqual("tR3+Ty81lMeYAr/Fid0kMTYa/WM=", "tR3+Ty81lMeYAr/Fid0kMTYa/WM="))
+	assert.False(t, constantTimeEqual("tR3+Ty81lMeYAr/Fid0kMTYa/WM=", "tR3+Ty81lMeYAr/Fid0kMTYa/WN="))
+	assert.False(t, constantTimeEqual("tR3+Ty81lMeYAr/Fid0kMTYa/WM=", "tR3+Ty81"))
+	assert.False(t, constantTimeEqual("", "tR3+Ty81"))
+	assert.True(t, constantTimeEqual("", ""))
+}
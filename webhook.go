@@ -0,0 +1,78 @@
+package oauth1
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// TwitterWebhookSignatureHeader carries the payload signature of Twitter
+// Account Activity API webhook requests.
+const TwitterWebhookSignatureHeader = "X-Twitter-Webhooks-Signature"
+
+// ErrWebhookSignature is returned when a webhook payload signature is
+// missing or invalid.
+var ErrWebhookSignature = errors.New("oauth1: Invalid webhook signature")
+
+// WebhookSignature returns "sha256=" followed by the base64 encoded
+// HMAC-SHA256 of message keyed with the consumer secret. It is both the
+// response token to a CRC challenge (with the crc_token as message) and the
+// signature of a webhook payload.
+func WebhookSignature(consumerSecret string, message []byte) string {
+	h := hmac.New(sha256.New, []byte(consumerSecret))
+	h.Write(message)
+	return "sha256=" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// CRCResponseToken returns the response_token answering the CRC challenge
+// crcToken.
+func CRCResponseToken(consumerSecret, crcToken string) string {
+	return WebhookSignature(consumerSecret, []byte(crcToken))
+}
+
+// ValidWebhookSignature reports, in constant time, whether signature is the
+// WebhookSignature of message.
+func ValidWebhookSignature(consumerSecret string, message []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(WebhookSignature(consumerSecret, message)))
+}
+
+// VerifyWebhookRequest reads the body of an incoming webhook request and
+// checks it against the signature carried in header, e.g.
+// TwitterWebhookSignatureHeader. The body is returned and also left readable
+// on req.
+func VerifyWebhookRequest(consumerSecret string, req *http.Request, header string) ([]byte, error) {
+	if req.Body == nil {
+		return nil, ErrWebhookSignature
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if !ValidWebhookSignature(consumerSecret, body, req.Header.Get(header)) {
+		return nil, ErrWebhookSignature
+	}
+	return body, nil
+}
+
+// CRCHandler returns an http.Handler answering CRC challenges, GET requests
+// with a crc_token query parameter, with a JSON response_token.
+func CRCHandler(consumerSecret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		crcToken := req.URL.Query().Get("crc_token")
+		if crcToken == "" {
+			http.Error(w, "crc_token missing", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"response_token": CRCResponseToken(consumerSecret, crcToken),
+		})
+	})
+}
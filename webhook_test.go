@@ -0,0 +1,49 @@
+package oauth1
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRCResponseToken(t *testing.T) {
+	// HMAC-SHA256("consumer_secret", "crc_token")
+	expected := "sha256=Yiv7Gks/8XLZqlzwP8O/jDPfmURHnYJz611wFu448G8="
+	assert.Equal(t, expected, CRCResponseToken("consumer_secret", "crc_token"))
+	assert.True(t, ValidWebhookSignature("consumer_secret", []byte("crc_token"), expected))
+	assert.False(t, ValidWebhookSignature("other_secret", []byte("crc_token"), expected))
+}
+
+func TestCRCHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CRCHandler("consumer_secret").ServeHTTP(rec, httptest.NewRequest("GET", "/webhook?crc_token=crc_token", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]string
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, CRCResponseToken("consumer_secret", "crc_token"), body["response_token"])
+
+	rec = httptest.NewRecorder()
+	CRCHandler("consumer_secret").ServeHTTP(rec, httptest.NewRequest("GET", "/webhook", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestVerifyWebhookRequest(t *testing.T) {
+	payload := `{"for_user_id":"2244994945"}`
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(payload))
+	req.Header.Set(TwitterWebhookSignatureHeader, WebhookSignature("consumer_secret", []byte(payload)))
+	body, err := VerifyWebhookRequest("consumer_secret", req, TwitterWebhookSignatureHeader)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, string(body))
+	reread, _ := ioutil.ReadAll(req.Body)
+	assert.Equal(t, payload, string(reread))
+
+	req = httptest.NewRequest("POST", "/webhook", strings.NewReader(payload))
+	req.Header.Set(TwitterWebhookSignatureHeader, "sha256=forged")
+	_, err = VerifyWebhookRequest("consumer_secret", req, TwitterWebhookSignatureHeader)
+	assert.Equal(t, ErrWebhookSignature, err)
+}
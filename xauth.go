@@ -0,0 +1,34 @@
+package oauth1
+
+import (
+	"context"
+	"net/url"
+)
+
+// AccessTokenXAuth obtains an access token and secret for the user with the
+// given username and password by POSTing an xAuth (client_auth mode) request
+// to the Endpoint AccessTokenURL, skipping the request token and
+// authorization steps. The credentials are sent in the signed form body.
+// Providers only grant xAuth to consumers they have approved for it.
+func (c *Config) AccessTokenXAuth(username, password string) (string, string, error) {
+	return c.AccessTokenXAuthContext(c.context(), username, password)
+}
+
+// AccessTokenXAuthContext is like AccessTokenXAuth but makes the request with
+// ctx.
+func (c *Config) AccessTokenXAuthContext(ctx context.Context, username, password string) (string, string, error) {
+	form := url.Values{
+		"x_auth_username": {username},
+		"x_auth_password": {password},
+		"x_auth_mode":     {"client_auth"},
+	}
+	values, err := c.tokenRequest(ctx, accessTokenURL, "", make(url.Values), form)
+	if err != nil {
+		return "", "", err
+	}
+	token, err := c.tokenFromValues(values)
+	if err != nil {
+		return "", "", err
+	}
+	return token.Token, token.TokenSecret, nil
+}
@@ -0,0 +1,53 @@
+package oauth1
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigAccessTokenXAuth(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		params := parseOAuthParamsOrFail(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "consumer_key", params["oauth_consumer_key"])
+		assert.NotContains(t, params, "oauth_token")
+		assert.NotContains(t, params, "x_auth_mode")
+		assert.Equal(t, "alice", req.PostFormValue("x_auth_username"))
+		assert.Equal(t, "p@ss word", req.PostFormValue("x_auth_password"))
+		assert.Equal(t, "client_auth", req.PostFormValue("x_auth_mode"))
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte(url.Values{
+			"oauth_token":        {"access_token"},
+			"oauth_token_secret": {"access_secret"},
+		}.Encode()))
+	})
+	defer server.Close()
+
+	config := &Config{
+		ConsumerKey: "consumer_key",
+		Endpoint:    Endpoint{AccessTokenURL: server.URL},
+	}
+	accessToken, accessSecret, err := config.AccessTokenXAuth("alice", "p@ss word")
+	assert.Nil(t, err)
+	assert.Equal(t, "access_token", accessToken)
+	assert.Equal(t, "access_secret", accessSecret)
+}
+
+func TestConfigAccessTokenXAuth_MissingTokenOrSecret(t *testing.T) {
+	server := newMockServer(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte("oauth_token=access_token"))
+	})
+	defer server.Close()
+
+	config := &Config{Endpoint: Endpoint{AccessTokenURL: server.URL}}
+	accessToken, accessSecret, err := config.AccessTokenXAuth("alice", "password")
+	if assert.Error(t, err) {
+		assert.Equal(t, "oauth1: Response missing oauth_token or oauth_token_secret", err.Error())
+	}
+	assert.Equal(t, "", accessToken)
+	assert.Equal(t, "", accessSecret)
+}